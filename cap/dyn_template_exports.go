@@ -0,0 +1,139 @@
+package cap
+
+import (
+	"context"
+
+	"github.com/capatazlib/go-capataz/internal/s"
+)
+
+// TemplateDynSupervisor is a DynSupervisor in which every child is spawned from
+// a template function that receives an argument of type T (e.g. a network
+// connection). It is a typed version of the DynSupervisor created with
+// NewSimpleOneForOneSupervisor, the equivalent of Erlang's simple_one_for_one
+// supervisor.
+//
+// Since: 0.3.0
+type TemplateDynSupervisor[T any] struct {
+	tdyn *s.TemplateDynSupervisor[T]
+}
+
+// NewDynSupervisorFromTemplate creates a TemplateDynSupervisor, which spawns
+// children from the given template function with the SpawnWith method. It
+// receives a context, the supervisor name (for tracing purposes) and the same
+// options a DynSupervisor receives. Given the children of this supervisor are
+// independent from each other, they always get restarted with the OneForOne
+// strategy.
+//
+// Example:
+//
+//	connSup, err := cap.NewDynSupervisorFromTemplate(
+//	  ctx,
+//	  "connections",
+//	  func(conn net.Conn) cap.Node {
+//	    return cap.NewWorker("conn", func(ctx context.Context) error {
+//	      return handleConn(ctx, conn)
+//	    }, cap.WithRestart(cap.Temporary))
+//	  },
+//	)
+//
+//	// spawns a child with a runtime name like connections/conn-000123
+//	_, cancelConn, err := connSup.SpawnWith(conn)
+//
+// Since: 0.3.0
+func NewDynSupervisorFromTemplate[T any](
+	ctx context.Context,
+	name string,
+	template func(arg T) Node,
+	opts ...Opt,
+) (*TemplateDynSupervisor[T], error) {
+	tdyn, err := s.NewDynSupervisorFromTemplate(ctx, name, template, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateDynSupervisor[T]{tdyn: tdyn}, nil
+}
+
+// SpawnWith creates a new child from the template function with the given
+// argument. The child gets a unique name composed of the name of the node
+// returned by the template and a sequence number (e.g. conn-000123). It returns
+// the runtime name of the child, and either a cancel/shutdown callback or an
+// error in the scenario the start of the child failed. This function blocks
+// until the child is started.
+//
+// Since: 0.3.0
+func (tdyn *TemplateDynSupervisor[T]) SpawnWith(arg T) (string, func() error, error) {
+	return tdyn.tdyn.SpawnWith(arg)
+}
+
+// SpawnWithContext accomplishes the same goal as SpawnWith; when the supervisor
+// was created with WithMaxChildren and the WaitForSlot policy, it returns a
+// MaxChildrenReachedError if the given context is done before the supervisor
+// is able to spawn the child.
+//
+// Since: 0.3.0
+func (tdyn *TemplateDynSupervisor[T]) SpawnWithContext(
+	ctx context.Context,
+	arg T,
+) (string, func() error, error) {
+	return tdyn.tdyn.SpawnWithContext(ctx, arg)
+}
+
+// Count returns the number of children that are running (or waiting for a
+// restart). It returns an error when the supervisor is terminated, or when the
+// given context is done before the supervisor replies.
+//
+// Since: 0.3.0
+func (tdyn *TemplateDynSupervisor[T]) Count(ctx context.Context) (int, error) {
+	return tdyn.tdyn.Count(ctx)
+}
+
+// List returns the runtime names of the children that are running (or waiting
+// for a restart), following the start order of the supervisor. It returns an
+// error when the supervisor is terminated, or when the given context is done
+// before the supervisor replies.
+//
+// Since: 0.3.0
+func (tdyn *TemplateDynSupervisor[T]) List(ctx context.Context) ([]string, error) {
+	return tdyn.tdyn.List(ctx)
+}
+
+// Inspect returns a snapshot of the runtime state of all the nodes in the
+// supervision tree. This function blocks until every supervisor in the tree
+// reports its children, or until the given context is done.
+//
+// Since: 0.3.0
+func (tdyn *TemplateDynSupervisor[T]) Inspect(ctx context.Context) (TreeSnapshot, error) {
+	return tdyn.tdyn.Inspect(ctx)
+}
+
+// Terminate is a synchronous procedure that halts the execution of the whole
+// supervision tree.
+//
+// Since: 0.3.0
+func (tdyn *TemplateDynSupervisor[T]) Terminate() error {
+	return tdyn.tdyn.Terminate()
+}
+
+// Wait blocks the execution of the current goroutine until the Supervisor
+// finishes it execution.
+//
+// Since: 0.3.0
+func (tdyn *TemplateDynSupervisor[T]) Wait() error {
+	return tdyn.tdyn.Wait()
+}
+
+// GetName returns the name of the Spec used to start this Supervisor
+//
+// Since: 0.3.0
+func (tdyn *TemplateDynSupervisor[T]) GetName() string {
+	return tdyn.tdyn.GetName()
+}
+
+// Drain stops the supervisor from spawning new children, and it waits for the
+// existing children to finish on their own before terminating the supervisor;
+// check DynSupervisor.Drain for more details.
+//
+// Since: 0.3.0
+func (tdyn *TemplateDynSupervisor[T]) Drain(ctx context.Context) error {
+	return tdyn.tdyn.Drain(ctx)
+}
@@ -1,6 +1,9 @@
 package cap
 
-import "github.com/capatazlib/go-capataz/internal/s"
+import (
+	"github.com/capatazlib/go-capataz/internal/c"
+	"github.com/capatazlib/go-capataz/internal/s"
+)
 
 // ErrKVs is an utility interface used to get key-values out of Capataz errors
 //
@@ -42,6 +45,84 @@ type SupervisorRestartError = s.SupervisorRestartError
 // Since: 0.0.0
 type RestartToleranceReached = s.RestartToleranceReached
 
+// PanicEscalatedError is the error that gets reported when a worker with the
+// EscalateOnPanic policy panicked, and its supervisor gave up on restarting its
+// children regardless of the restart tolerance; it unwraps to the PanicError
+// of the worker.
+//
+// Since: 0.3.0
+type PanicEscalatedError = s.PanicEscalatedError
+
+// NodeNotFoundError is the error returned by the Supervisor TerminateChild,
+// RestartChild and DeleteChild methods when the given runtime name does not
+// belong to a node of the supervision tree.
+//
+// Since: 0.3.0
+type NodeNotFoundError = s.NodeNotFoundError
+
+// NodeRunningError is the error returned by the Supervisor RestartChild and
+// DeleteChild methods when the node with the given runtime name is running.
+//
+// Since: 0.3.0
+type NodeRunningError = s.NodeRunningError
+
+// NodeExistsError is the error returned by the Supervisor StartChild method
+// when a sibling node with the same name already exists.
+//
+// Since: 0.3.0
+type NodeExistsError = s.NodeExistsError
+
+// MaxChildrenReachedError is the error returned by the spawn calls of a
+// supervisor that already supervises the maximum number of children specified
+// with WithMaxChildren. It matches ErrMaxChildrenReached when using errors.Is.
+//
+// Since: 0.3.0
+type MaxChildrenReachedError = s.MaxChildrenReachedError
+
+// ErrMaxChildrenReached is the value that matches (using errors.Is) a
+// MaxChildrenReachedError
+//
+// Since: 0.3.0
+var ErrMaxChildrenReached = s.ErrMaxChildrenReached
+
+// SupervisorDrainingError is the error returned by the spawn calls of a
+// DynSupervisor after its drain started (see DynSupervisor.Drain)
+//
+// Since: 0.3.0
+type SupervisorDrainingError = s.SupervisorDrainingError
+
+// LivenessTimeoutError is the error reported on the ProcessFailed event of a
+// worker that did not pass its liveness probe or missed a heartbeat (see
+// WithLivenessProbe and WithHeartbeat). When the worker does not stop within
+// its shutdown timeout, it gets abandoned and errors.As also finds a
+// ShutdownTimeoutError in this error.
+//
+// Since: 0.3.0
+type LivenessTimeoutError = c.LivenessTimeoutError
+
+// StartTimeoutError is the error reported by a worker that did not notify it
+// started within the duration specified with WithStartTimeout
+//
+// Since: 0.3.0
+type StartTimeoutError = c.StartTimeoutError
+
+// ShutdownTimeoutError is the error reported by a process that did not stop
+// within the duration specified by its Shutdown value; the parent supervisor
+// abandons the process, which may keep running in the background (see
+// AbandonedProcesses)
+//
+// Since: 0.3.0
+type ShutdownTimeoutError = c.ShutdownTimeoutError
+
+// PanicError is the error reported when a worker or a build nodes function
+// panics; it holds the value given to panic and the stack trace of the
+// goroutine that panicked. The stack trace of a worker panic is included in the
+// output of ExplainError; a SupervisorBuildError keeps the panic value as its
+// message, and it unwraps to the PanicError.
+//
+// Since: 0.3.0
+type PanicError = c.PanicError
+
 // ExplainError is a utility function that explains capataz errors in a human-friendly
 // way. Defaults to a call to error.Error() if the underlying error does not come from
 // the capataz library.
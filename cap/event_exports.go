@@ -37,6 +37,71 @@ var ProcessFailed = s.ProcessFailed
 // Since: 0.0.0
 var ProcessCompleted = s.ProcessCompleted
 
+// ChildRestartToleranceReached is an Event that indicates a process surpassed
+// its own restart tolerance (specified with the WithTolerance worker option)
+//
+// Since: 0.3.0
+var ChildRestartToleranceReached = s.ChildRestartToleranceReached
+
+// ProcessDrained is an Event that indicates a process finished without errors
+// while its supervisor was draining (see DynSupervisor.Drain)
+//
+// Since: 0.3.0
+var ProcessDrained = s.ProcessDrained
+
+// ProcessForceTerminated is an Event that indicates a process was stopped by a
+// parent supervisor because it did not finish before the drain of the
+// supervisor was done (see DynSupervisor.Drain)
+//
+// Since: 0.3.0
+var ProcessForceTerminated = s.ProcessForceTerminated
+
+// ProcessReady is an Event that indicates a worker that was created with the
+// WithReadiness option reported it is ready
+//
+// Since: 0.3.0
+var ProcessReady = s.ProcessReady
+
+// ProcessAbandoned is an Event that indicates a process did not stop within its
+// shutdown timeout and its parent supervisor gave up on it; the goroutine of
+// the process may still be running (see AbandonedProcesses)
+//
+// Since: 0.3.0
+var ProcessAbandoned = s.ProcessAbandoned
+
+// ProcessAbandonedExited is an Event that indicates the goroutine of an
+// abandoned process finished its execution; this event is only reported when
+// the parent supervisor of the process is still running
+//
+// Since: 0.3.0
+var ProcessAbandonedExited = s.ProcessAbandonedExited
+
+// SupervisorRestarting is an Event that indicates a supervisor is about to
+// execute its restart strategy; the child that caused the restart, the restart
+// count and the restart window of the supervisor are available on the Event
+//
+// Since: 0.3.0
+var SupervisorRestarting = s.SupervisorRestarting
+
+// RestartToleranceIncremented is an Event that indicates the restart count of
+// a process restart tolerance was incremented. This event is emitted by
+// supervisors and by workers that use the WithTolerance option
+//
+// Since: 0.3.0
+var RestartToleranceIncremented = s.RestartToleranceIncremented
+
+// RestartToleranceWindowReset is an Event that indicates the restart tolerance
+// window of a process expired and that its restart count started over
+//
+// Since: 0.3.0
+var RestartToleranceWindowReset = s.RestartToleranceWindowReset
+
+// ChildRestartScheduled is an Event that indicates the restart of a process is
+// going to happen after a delay (see WithRestartBackoff)
+//
+// Since: 0.3.0
+var ChildRestartScheduled = s.ChildRestartScheduled
+
 // Event is a record emitted by the supervision system. The events are used for
 // multiple purposes, from testing to monitoring the healthiness of the
 // supervision system.
@@ -85,6 +150,70 @@ var WithNotifierTimeout = n.WithNotifierTimeout
 // Since: 0.1.0
 var WithOnReliableNotifierFailure = n.WithOnReliableNotifierFailure
 
+// WithEntrypointBufferSize sets the size of the buffer that holds the events
+// reported by the supervision system before they get broadcasted to the
+// notifiers of a ReliableNotifier (defaults to 0)
+//
+// Since: 0.3.0
+var WithEntrypointBufferSize = n.WithEntrypointBufferSize
+
+// WithNotifierBufferSize sets the size of the buffer that holds the events for
+// the notifier with the given name on a ReliableNotifier (defaults to 0)
+//
+// Since: 0.3.0
+var WithNotifierBufferSize = n.WithNotifierBufferSize
+
+// WithNotifierQueueSize sets the number of events that can wait to be delivered
+// to each notifier of a ReliableNotifier (defaults to 100). Events that do not
+// fit on the queue of a notifier are handled by the OverflowPolicy
+//
+// Since: 0.3.0
+var WithNotifierQueueSize = n.WithNotifierQueueSize
+
+// OverflowPolicy specifies what a ReliableNotifier does with an event when the
+// buffer of the entrypoint or the queue of a notifier is full
+//
+// Since: 0.3.0
+type OverflowPolicy = n.OverflowPolicy
+
+// OverflowBlock is an OverflowPolicy that waits until there is space on the
+// buffer; notifiers that are not ready within the notifier timeout get the
+// event skipped. This is the default policy.
+//
+// Since: 0.3.0
+var OverflowBlock = n.OverflowBlock
+
+// OverflowDropOldest is an OverflowPolicy that discards the oldest event on the
+// buffer to make space for the new event
+//
+// Since: 0.3.0
+var OverflowDropOldest = n.OverflowDropOldest
+
+// OverflowDropNewest is an OverflowPolicy that discards the new event
+//
+// Since: 0.3.0
+var OverflowDropNewest = n.OverflowDropNewest
+
+// WithOverflowPolicy sets what happens with an event when the buffer of the
+// entrypoint or the queue of a notifier of a ReliableNotifier is full. With
+// OverflowDropOldest and OverflowDropNewest, the ReliableNotifier never blocks
+// the supervision system.
+//
+// Since: 0.3.0
+var WithOverflowPolicy = n.WithOverflowPolicy
+
+// DropCounter keeps track of the number of events a ReliableNotifier did not
+// deliver to each of its notifiers. The zero value is ready to use.
+//
+// Since: 0.3.0
+type DropCounter = n.DropCounter
+
+// WithDropCounter sets a DropCounter that keeps track of the number of events
+// each notifier of a ReliableNotifier did not receive
+//
+// Since: 0.3.0
+var WithDropCounter = n.WithDropCounter
+
 // EventCriteria is an utility that allows us to specify a matching criteria to
 // a specific supervision event
 //
@@ -141,6 +270,12 @@ var EHasRuntimeName = n.EHasRuntimeName
 // Since: 0.1.0
 var EHasNameSuffix = n.EHasNameSuffix
 
+// EHasLabel returns true if the node that emitted the event has a label with
+// the given key and value (see WithLabels and WithSupervisorLabels)
+//
+// Since: 0.3.0
+var EHasLabel = n.EHasLabel
+
 // ApplyEventCriteria forwards Event records that match positively the given
 // criteria to the given EventNotifier
 //
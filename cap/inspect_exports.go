@@ -0,0 +1,43 @@
+package cap
+
+import "github.com/capatazlib/go-capataz/internal/s"
+
+// NodeState specifies the runtime state of a node in a supervision tree
+//
+// Since: 0.3.0
+type NodeState = s.NodeState
+
+// NodeRunning indicates the node goroutine is running
+//
+// Since: 0.3.0
+var NodeRunning = s.NodeRunning
+
+// NodeRestarting indicates the node goroutine finished and it is waiting for a
+// delayed restart (see WithRestartBackoff)
+//
+// Since: 0.3.0
+var NodeRestarting = s.NodeRestarting
+
+// NodeStopped indicates the node goroutine finished and it is not going to be
+// restarted by its supervisor (e.g. a Transient node that completed)
+//
+// Since: 0.3.0
+var NodeStopped = s.NodeStopped
+
+// NodeSnapshot contains the runtime information of a node in a supervision
+// tree at the moment a snapshot was taken. It contains the node runtime name,
+// NodeTag, Restart and Shutdown settings, restart count, uptime and NodeState.
+//
+// Since: 0.3.0
+type NodeSnapshot = s.NodeSnapshot
+
+// TreeSnapshot contains the runtime information of all the nodes of a
+// supervision tree. You can get a TreeSnapshot with the Inspect method of a
+// Supervisor or DynSupervisor.
+//
+// Every supervisor of the tree reports the state of its own children from its
+// own goroutine, given this, a TreeSnapshot is consistent per supervisor, but
+// sub-trees may be inspected at slightly different moments.
+//
+// Since: 0.3.0
+type TreeSnapshot = s.TreeSnapshot
@@ -0,0 +1,432 @@
+// Package metrics offers a Prometheus collector that keeps track of the health
+// of a capataz supervision system.
+//
+// A Collector gets the events of the supervision system through its
+// EventNotifier method, and it exposes the following metrics:
+//
+// * capataz_events_total: number of events reported by the supervision
+// system, by event tag.
+//
+// * capataz_process_restarts_total: number of times a process was started
+// again, by runtime name and node tag.
+//
+// * capataz_process_failures_total: number of process failures, by runtime
+// name, node tag and error type. The error type is one of panic,
+// panic_escalated, restart_tolerance, build, start_timeout, liveness_timeout
+// and shutdown_timeout for the errors of capataz, and other for the errors
+// returned by workers.
+//
+// * capataz_process_uptime_seconds: time since a running process started, by
+// runtime name and node tag.
+//
+// * capataz_restart_tolerance_restarts and
+// capataz_restart_tolerance_max_restarts: restarts accounted on the current
+// restart window, and the restarts allowed on it, by runtime name and node tag.
+//
+// * capataz_supervisor_running_children: number of running children of each
+// supervisor (including DynSupervisor), by runtime name.
+//
+// * capataz_notifier_dropped_events_total: number of events a ReliableNotifier
+// did not deliver, by notifier name (see WithDropCounter).
+//
+// The series of a process are kept after it stops, so that its counters are
+// not lost when it gets restarted. To bound the memory used by supervisors that
+// spawn many short-lived children (e.g. a DynSupervisor), the Collector only
+// keeps the series of the last stopped processes that did not start again (see
+// WithMaxStoppedProcesses); the series of older stopped processes are evicted.
+//
+// The Handler method serves these metrics using the Prometheus text exposition
+// format. Use it when the application does not have a Prometheus registry,
+// otherwise register the Collector on the existing registry.
+//
+// Since: 0.3.0
+package metrics
+
+import (
+	"container/list"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/capatazlib/go-capataz/cap"
+)
+
+// processKey identifies a process of the supervision system
+type processKey struct {
+	runtimeName string
+	nodeTag     cap.NodeTag
+}
+
+// defaultMaxStoppedProcesses is the number of stopped processes a Collector
+// keeps series for
+const defaultMaxStoppedProcesses = 1000
+
+// toleranceState is the restart tolerance consumption of a process
+type toleranceState struct {
+	restartCount    uint32
+	maxRestartCount uint32
+}
+
+// Opt allows clients to tweak the behavior of a Collector
+//
+// Since: 0.3.0
+type Opt func(*Collector)
+
+// WithNamespace sets the prefix of the metric names (defaults to "capataz")
+//
+// Since: 0.3.0
+func WithNamespace(namespace string) Opt {
+	return func(col *Collector) {
+		col.namespace = namespace
+	}
+}
+
+// WithMaxStoppedProcesses sets the number of processes that stopped and did not
+// start again for which the Collector keeps series (defaults to 1000). When
+// this number is surpassed, the series of the process that stopped first are
+// evicted; if that process starts again later, its restart count starts over.
+//
+// This function panics if max is negative.
+//
+// Since: 0.3.0
+func WithMaxStoppedProcesses(max int) Opt {
+	if max < 0 {
+		panic("max stopped processes must not be negative")
+	}
+	return func(col *Collector) {
+		col.maxStoppedProcesses = max
+	}
+}
+
+// WithDropCounter sets the DropCounter of a ReliableNotifier, the Collector
+// exposes the events each notifier did not receive
+//
+// Since: 0.3.0
+func WithDropCounter(dc *cap.DropCounter) Opt {
+	return func(col *Collector) {
+		col.dropCounter = dc
+	}
+}
+
+// Collector is a prometheus.Collector that keeps track of the events of a
+// supervision system. Use the EventNotifier method to feed it.
+//
+// Since: 0.3.0
+type Collector struct {
+	namespace           string
+	dropCounter         *cap.DropCounter
+	maxStoppedProcesses int
+
+	eventsDesc        *prometheus.Desc
+	restartsDesc      *prometheus.Desc
+	failuresDesc      *prometheus.Desc
+	uptimeDesc        *prometheus.Desc
+	toleranceDesc     *prometheus.Desc
+	maxToleranceDesc  *prometheus.Desc
+	childrenDesc      *prometheus.Desc
+	droppedEventsDesc *prometheus.Desc
+
+	mux       sync.Mutex
+	events    map[cap.EventTag]uint64
+	started   map[processKey]struct{}
+	restarts  map[processKey]uint64
+	failures  map[processKey]map[string]uint64
+	running   map[string]runningProcess
+	tolerance map[processKey]toleranceState
+
+	// stopped holds the processKey of the processes that stopped and did not
+	// start again, ordered by the time they stopped
+	stopped      *list.List
+	stoppedElems map[processKey]*list.Element
+}
+
+// runningProcess is a process of the supervision system that has not stopped
+// yet
+type runningProcess struct {
+	nodeTag   cap.NodeTag
+	startTime time.Time
+}
+
+// NewCollector creates a Collector
+//
+// Since: 0.3.0
+func NewCollector(opts ...Opt) *Collector {
+	col := &Collector{
+		namespace:           "capataz",
+		maxStoppedProcesses: defaultMaxStoppedProcesses,
+		events:              make(map[cap.EventTag]uint64),
+		started:             make(map[processKey]struct{}),
+		restarts:            make(map[processKey]uint64),
+		failures:            make(map[processKey]map[string]uint64),
+		running:             make(map[string]runningProcess),
+		tolerance:           make(map[processKey]toleranceState),
+		stopped:             list.New(),
+		stoppedElems:        make(map[processKey]*list.Element),
+	}
+
+	for _, optFn := range opts {
+		optFn(col)
+	}
+
+	processLabels := []string{"runtime_name", "node_tag"}
+
+	col.eventsDesc = col.newDesc(
+		"events_total",
+		"Number of events reported by the supervision system.",
+		"tag",
+	)
+	col.restartsDesc = col.newDesc(
+		"process_restarts_total",
+		"Number of times a process was started again.",
+		processLabels...,
+	)
+	col.failuresDesc = col.newDesc(
+		"process_failures_total",
+		"Number of times a process failed, by error type.",
+		append(processLabels, "error_type")...,
+	)
+	col.uptimeDesc = col.newDesc(
+		"process_uptime_seconds",
+		"Time since a running process started.",
+		processLabels...,
+	)
+	col.toleranceDesc = col.newDesc(
+		"restart_tolerance_restarts",
+		"Number of restarts accounted on the current restart window.",
+		processLabels...,
+	)
+	col.maxToleranceDesc = col.newDesc(
+		"restart_tolerance_max_restarts",
+		"Number of restarts allowed on a restart window.",
+		processLabels...,
+	)
+	col.childrenDesc = col.newDesc(
+		"supervisor_running_children",
+		"Number of running children of a supervisor.",
+		"runtime_name",
+	)
+	col.droppedEventsDesc = col.newDesc(
+		"notifier_dropped_events_total",
+		"Number of events a notifier of a ReliableNotifier did not receive.",
+		"notifier",
+	)
+
+	return col
+}
+
+// newDesc builds a prometheus.Desc with the namespace of the Collector
+func (col *Collector) newDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(col.namespace, "", name),
+		help,
+		labels,
+		nil,
+	)
+}
+
+// EventNotifier returns a cap.EventNotifier that records the events of the
+// supervision system on the Collector
+//
+// Since: 0.3.0
+func (col *Collector) EventNotifier() cap.EventNotifier {
+	return col.handleEvent
+}
+
+// handleEvent records the given event on the Collector
+func (col *Collector) handleEvent(ev cap.Event) {
+	col.mux.Lock()
+	defer col.mux.Unlock()
+
+	col.events[ev.GetTag()]++
+	key := processKey{runtimeName: ev.GetProcessRuntimeName(), nodeTag: ev.GetNodeTag()}
+
+	switch ev.GetTag() {
+	case cap.ProcessStarted:
+		if _, ok := col.started[key]; ok {
+			col.restarts[key]++
+		}
+		col.started[key] = struct{}{}
+		col.running[key.runtimeName] = runningProcess{
+			nodeTag:   key.nodeTag,
+			startTime: ev.GetCreated(),
+		}
+		if elem, ok := col.stoppedElems[key]; ok {
+			col.stopped.Remove(elem)
+			delete(col.stoppedElems, key)
+		}
+
+	case cap.ProcessFailed, cap.ProcessStartFailed:
+		if _, ok := col.failures[key]; !ok {
+			col.failures[key] = make(map[string]uint64)
+		}
+		col.failures[key][errorType(ev.Err())]++
+		col.processStopped(key)
+
+	case cap.ProcessTerminated, cap.ProcessCompleted, cap.ProcessDrained,
+		cap.ProcessForceTerminated:
+		col.processStopped(key)
+
+	case cap.RestartToleranceIncremented, cap.RestartToleranceWindowReset:
+		col.tolerance[key] = toleranceState{
+			restartCount:    ev.GetRestartCount(),
+			maxRestartCount: ev.GetMaxRestartCount(),
+		}
+	}
+}
+
+// processStopped records the given process is not running anymore, and evicts
+// the series of the processes that stopped first when there are more stopped
+// processes than the Collector keeps
+func (col *Collector) processStopped(key processKey) {
+	delete(col.running, key.runtimeName)
+
+	if elem, ok := col.stoppedElems[key]; ok {
+		col.stopped.MoveToBack(elem)
+	} else {
+		col.stoppedElems[key] = col.stopped.PushBack(key)
+	}
+
+	for col.stopped.Len() > col.maxStoppedProcesses {
+		evicted, _ := col.stopped.Remove(col.stopped.Front()).(processKey)
+		delete(col.stoppedElems, evicted)
+		delete(col.started, evicted)
+		delete(col.restarts, evicted)
+		delete(col.failures, evicted)
+		delete(col.tolerance, evicted)
+	}
+}
+
+// errorType returns a fixed name for the given error, so that the error_type
+// label has a bounded set of values
+func errorType(err error) string {
+	var (
+		restartErr       *cap.SupervisorRestartError
+		panicEscalateErr *cap.PanicEscalatedError
+		buildErr         *cap.SupervisorBuildError
+		startTimeoutErr  *cap.StartTimeoutError
+		livenessErr      *cap.LivenessTimeoutError
+		shutdownErr      *cap.ShutdownTimeoutError
+		panicErr         *cap.PanicError
+	)
+	// errors that wrap other capataz errors are checked first
+	switch {
+	case errors.As(err, &restartErr):
+		return "restart_tolerance"
+	case errors.As(err, &panicEscalateErr):
+		return "panic_escalated"
+	case errors.As(err, &buildErr):
+		return "build"
+	case errors.As(err, &startTimeoutErr):
+		return "start_timeout"
+	case errors.As(err, &livenessErr):
+		return "liveness_timeout"
+	case errors.As(err, &shutdownErr):
+		return "shutdown_timeout"
+	case errors.As(err, &panicErr):
+		return "panic"
+	default:
+		return "other"
+	}
+}
+
+// Describe implements the prometheus.Collector interface
+//
+// Since: 0.3.0
+func (col *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- col.eventsDesc
+	ch <- col.restartsDesc
+	ch <- col.failuresDesc
+	ch <- col.uptimeDesc
+	ch <- col.toleranceDesc
+	ch <- col.maxToleranceDesc
+	ch <- col.childrenDesc
+	ch <- col.droppedEventsDesc
+}
+
+// Collect implements the prometheus.Collector interface
+//
+// Since: 0.3.0
+func (col *Collector) Collect(ch chan<- prometheus.Metric) {
+	col.mux.Lock()
+	defer col.mux.Unlock()
+
+	now := time.Now()
+
+	for tag, count := range col.events {
+		ch <- prometheus.MustNewConstMetric(
+			col.eventsDesc, prometheus.CounterValue, float64(count), tag.String(),
+		)
+	}
+
+	for key, count := range col.restarts {
+		ch <- prometheus.MustNewConstMetric(
+			col.restartsDesc, prometheus.CounterValue, float64(count),
+			key.runtimeName, key.nodeTag.String(),
+		)
+	}
+
+	for key, errTypes := range col.failures {
+		for errType, count := range errTypes {
+			ch <- prometheus.MustNewConstMetric(
+				col.failuresDesc, prometheus.CounterValue, float64(count),
+				key.runtimeName, key.nodeTag.String(), errType,
+			)
+		}
+	}
+
+	children := make(map[string]uint64)
+	for name, process := range col.running {
+		ch <- prometheus.MustNewConstMetric(
+			col.uptimeDesc, prometheus.GaugeValue, now.Sub(process.startTime).Seconds(),
+			name, process.nodeTag.String(),
+		)
+		if _, ok := children[name]; !ok && process.nodeTag == cap.SupervisorT {
+			children[name] = 0
+		}
+		if sepIx := strings.LastIndex(name, cap.NodeSepToken); sepIx >= 0 {
+			if _, ok := col.running[name[:sepIx]]; ok {
+				children[name[:sepIx]]++
+			}
+		}
+	}
+
+	for name, count := range children {
+		ch <- prometheus.MustNewConstMetric(
+			col.childrenDesc, prometheus.GaugeValue, float64(count), name,
+		)
+	}
+
+	for key, state := range col.tolerance {
+		ch <- prometheus.MustNewConstMetric(
+			col.toleranceDesc, prometheus.GaugeValue, float64(state.restartCount),
+			key.runtimeName, key.nodeTag.String(),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			col.maxToleranceDesc, prometheus.GaugeValue, float64(state.maxRestartCount),
+			key.runtimeName, key.nodeTag.String(),
+		)
+	}
+
+	if col.dropCounter != nil {
+		for name, count := range col.dropCounter.Snapshot() {
+			ch <- prometheus.MustNewConstMetric(
+				col.droppedEventsDesc, prometheus.CounterValue, float64(count), name,
+			)
+		}
+	}
+}
+
+// Handler returns an http.Handler that serves the metrics of the Collector
+// using the Prometheus text exposition format
+//
+// Since: 0.3.0
+func (col *Collector) Handler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(col)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
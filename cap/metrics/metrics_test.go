@@ -0,0 +1,238 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	"github.com/capatazlib/go-capataz/cap/metrics"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+// scrape returns the metrics served by the given server
+func scrape(t *testing.T, server *httptest.Server) string {
+	resp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return string(body)
+}
+
+// assertMetric asserts the given metric sample is present on the given
+// scrape output
+func assertMetric(t *testing.T, output, sample string) {
+	for _, line := range strings.Split(output, "\n") {
+		if line == sample {
+			return
+		}
+	}
+	assert.Fail(t, "metric sample not found", "sample: %s\noutput:\n%s", sample, output)
+}
+
+func TestCollector(t *testing.T) {
+	child1, failWorker1 := FailOnSignalWorker(1, "child1", cap.WithRestart(cap.Permanent))
+
+	var dropCounter cap.DropCounter
+	col := metrics.NewCollector(metrics.WithDropCounter(&dropCounter))
+	server := httptest.NewServer(col.Handler())
+	defer server.Close()
+
+	dynSubtree := cap.NewDynSubtree(
+		"dyn",
+		func(ctx context.Context, spawner cap.Spawner) error {
+			for _, name := range []string{"dyn-child1", "dyn-child2"} {
+				if _, err := spawner.Spawn(WaitDoneWorker(name)); err != nil {
+					return err
+				}
+			}
+			<-ctx.Done()
+			return nil
+		},
+		[]cap.Opt{},
+	)
+
+	var output string
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1, dynSubtree),
+		[]cap.Opt{cap.WithRestartTolerance(3, 10*time.Second)},
+		[]cap.EventNotifier{col.EventNotifier()},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			failWorker1(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+
+			// the dynamic children are spawned concurrently
+			dynIt := em.Iterator()
+			dynIt.SkipTill(WorkerStarted("root/dyn/subtree/dyn-child2"))
+
+			output = scrape(t, server)
+		},
+	)
+	assert.NoError(t, err)
+
+	assertMetric(t, output, `capataz_events_total{tag="ProcessFailed"} 1`)
+	assertMetric(t, output,
+		`capataz_process_restarts_total{node_tag="Worker",runtime_name="root/child1"} 1`,
+	)
+	assertMetric(t, output,
+		`capataz_process_failures_total{error_type="other",node_tag="Worker",runtime_name="root/child1"} 1`,
+	)
+	assertMetric(t, output,
+		`capataz_restart_tolerance_restarts{node_tag="Supervisor",runtime_name="root"} 1`,
+	)
+	assertMetric(t, output,
+		`capataz_restart_tolerance_max_restarts{node_tag="Supervisor",runtime_name="root"} 3`,
+	)
+	assertMetric(t, output, `capataz_supervisor_running_children{runtime_name="root"} 2`)
+	assertMetric(t, output,
+		`capataz_supervisor_running_children{runtime_name="root/dyn/subtree"} 2`,
+	)
+	assert.Contains(t, output,
+		`capataz_process_uptime_seconds{node_tag="Worker",runtime_name="root/dyn/subtree/dyn-child1"} `,
+	)
+
+	// processes that are not running do not report uptime
+	output = scrape(t, server)
+	assert.NotContains(t, output, "capataz_process_uptime_seconds{")
+	assert.NotContains(t, output, "capataz_supervisor_running_children{")
+}
+
+func TestCollectorErrorTypes(t *testing.T) {
+	child1, panicWorker1 := PanicOnSignalWorker(1, "child1")
+	child2, failWorker2 := FailOnSignalWorker(1, "child2")
+
+	col := metrics.NewCollector()
+	server := httptest.NewServer(col.Handler())
+	defer server.Close()
+
+	var output string
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1, child2),
+		[]cap.Opt{cap.WithRestartTolerance(3, 10*time.Second)},
+		[]cap.EventNotifier{col.EventNotifier()},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			panicWorker1(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+			failWorker2(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child2"))
+
+			output = scrape(t, server)
+		},
+	)
+	assert.NoError(t, err)
+
+	// errors of capataz get a fixed name, other errors are reported as other
+	assertMetric(t, output,
+		`capataz_process_failures_total{error_type="panic",node_tag="Worker",runtime_name="root/child1"} 1`,
+	)
+	assertMetric(t, output,
+		`capataz_process_failures_total{error_type="other",node_tag="Worker",runtime_name="root/child2"} 1`,
+	)
+}
+
+func TestCollectorEvictsStoppedProcesses(t *testing.T) {
+	col := metrics.NewCollector(metrics.WithMaxStoppedProcesses(1))
+	server := httptest.NewServer(col.Handler())
+	defer server.Close()
+
+	names := []string{"dyn-child1", "dyn-child2", "dyn-child3"}
+	dynSubtree := cap.NewDynSubtree(
+		"dyn",
+		func(ctx context.Context, spawner cap.Spawner) error {
+			for _, name := range names {
+				failingWorker := cap.NewWorker(
+					name,
+					func(context.Context) error { return errors.New("failing worker") },
+					cap.WithRestart(cap.Temporary),
+				)
+				if _, err := spawner.Spawn(failingWorker); err != nil {
+					return err
+				}
+			}
+			<-ctx.Done()
+			return nil
+		},
+		[]cap.Opt{cap.WithRestartTolerance(10, 10*time.Second)},
+	)
+
+	var output string
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(dynSubtree),
+		[]cap.Opt{},
+		[]cap.EventNotifier{col.EventNotifier()},
+		func(em EventManager) {
+			// the dynamic children fail concurrently
+			for _, name := range names {
+				evIt := em.Iterator()
+				evIt.SkipTill(WorkerFailed("root/dyn/subtree/" + name))
+			}
+			output = scrape(t, server)
+		},
+	)
+	assert.NoError(t, err)
+
+	// only the series of the last failed child are kept
+	assertMetric(t, output, `capataz_events_total{tag="ProcessFailed"} 3`)
+	assert.Equal(t, 1, strings.Count(output, "capataz_process_failures_total{"))
+}
+
+func TestCollectorDropCounter(t *testing.T) {
+	var dropCounter cap.DropCounter
+	col := metrics.NewCollector(
+		metrics.WithNamespace("app"),
+		metrics.WithDropCounter(&dropCounter),
+	)
+	server := httptest.NewServer(col.Handler())
+	defer server.Close()
+
+	// a notifier that takes longer than the notifier timeout
+	slowNotifier := func(cap.Event) { time.Sleep(50 * time.Millisecond) }
+	evNotifier, cancelEvNotifier, err := cap.NewReliableNotifier(
+		map[string]cap.EventNotifier{"slow": slowNotifier},
+		cap.WithNotifierTimeout(time.Millisecond),
+		cap.WithDropCounter(&dropCounter),
+	)
+	assert.NoError(t, err)
+	defer cancelEvNotifier()
+
+	_, err = ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(WaitDoneWorker("child1")),
+		[]cap.Opt{},
+		[]cap.EventNotifier{evNotifier},
+		func(EventManager) {},
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 100 && dropCounter.Get("slow") == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	output := scrape(t, server)
+	assert.Contains(t, output, `app_notifier_dropped_events_total{notifier="slow"} `)
+}
@@ -0,0 +1,259 @@
+// Package otelcap offers an OpenTelemetry tracing adapter for a capataz
+// supervision system.
+//
+// A Tracer creates a span for each run of a node of the supervision tree; the
+// span starts when the node starts, and ends when the node fails, completes or
+// terminates. The span of a node is nested under the span of its supervisor,
+// and the span of a restarted node is linked to the span of its previous run.
+//
+// The context of each worker carries the span of its current run, so that the
+// spans created by the worker logic join the trace of the supervision tree.
+//
+// A Tracer requires two hooks in the root supervisor:
+//
+//	tracer := otelcap.NewTracer(tracerProvider)
+//
+//	cap.NewSupervisorSpec(
+//	  "root",
+//	  cap.WithNodes(...),
+//	  cap.WithNodeContext(tracer.NodeContext),
+//	  cap.WithNotifier(tracer.EventNotifier()),
+//	)
+//
+// The Tracer matches the events of a node with its current run by runtime
+// name, so its EventNotifier must receive the events synchronously: do not
+// wrap it in a ReliableNotifier (or any other notifier that delivers events on
+// a different goroutine), otherwise a late event of a previous run may end the
+// span of the current run.
+//
+// To link restarts, the Tracer keeps the span of the last run of the nodes that
+// stopped; it only keeps the spans of the last stopped nodes that did not start
+// again (see WithMaxStoppedNodes).
+//
+// Since: 0.3.0
+package otelcap
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/capatazlib/go-capataz/cap"
+)
+
+// instrumentationName is the name of the tracer created from the
+// TracerProvider given to NewTracer
+const instrumentationName = "github.com/capatazlib/go-capataz/cap/otelcap"
+
+// defaultMaxStoppedNodes is the number of stopped nodes a Tracer keeps the last
+// span for
+const defaultMaxStoppedNodes = 1000
+
+// Span attribute keys set on the spans of the nodes
+const (
+	// RuntimeNameKey is the runtime name of the node of the span
+	RuntimeNameKey = attribute.Key("capataz.runtime_name")
+	// NodeTagKey is the tag (Worker or Supervisor) of the node of the span
+	NodeTagKey = attribute.Key("capataz.node_tag")
+	// LinkKey is set on the link from the span of a restarted node to the span
+	// of its previous run
+	LinkKey = attribute.Key("capataz.link")
+)
+
+// Opt allows clients to tweak the behavior of a Tracer
+//
+// Since: 0.3.0
+type Opt func(*Tracer)
+
+// WithMaxStoppedNodes sets the number of nodes that stopped and did not start
+// again for which the Tracer keeps the span of their last run (defaults to
+// 1000). When this number is surpassed, the span of the node that stopped first
+// is forgotten; if that node starts again later, its span is not linked to the
+// previous run.
+//
+// This function panics if max is negative.
+//
+// Since: 0.3.0
+func WithMaxStoppedNodes(max int) Opt {
+	if max < 0 {
+		panic("max stopped nodes must not be negative")
+	}
+	return func(t *Tracer) {
+		t.maxStoppedNodes = max
+	}
+}
+
+// Tracer turns the events of a supervision system into OpenTelemetry spans.
+//
+// Since: 0.3.0
+type Tracer struct {
+	tracer          trace.Tracer
+	maxStoppedNodes int
+
+	mux sync.Mutex
+	// active contains the spans of the nodes that are running
+	active map[string]trace.Span
+	// finished contains the span context of the last run of the nodes that are
+	// not running, used to link restarts; the list elements are ordered by the
+	// time the nodes stopped
+	finished      map[string]*list.Element
+	finishedOrder *list.List
+}
+
+// finishedRun is the last run of a node that is not running
+type finishedRun struct {
+	runtimeName string
+	spanCtx     trace.SpanContext
+}
+
+// NewTracer creates a Tracer that creates spans with the given TracerProvider
+//
+// Since: 0.3.0
+func NewTracer(tp trace.TracerProvider, opts ...Opt) *Tracer {
+	t := &Tracer{
+		tracer:          tp.Tracer(instrumentationName),
+		maxStoppedNodes: defaultMaxStoppedNodes,
+		active:          make(map[string]trace.Span),
+		finished:        make(map[string]*list.Element),
+		finishedOrder:   list.New(),
+	}
+
+	for _, optFn := range opts {
+		optFn(t)
+	}
+
+	return t
+}
+
+// NodeContext starts the span of a node run, it returns a context that carries
+// the span. This method is a cap.NodeContextFn, use it with the
+// cap.WithNodeContext option.
+//
+// Since: 0.3.0
+func (t *Tracer) NodeContext(
+	ctx context.Context,
+	runtimeName string,
+	nodeTag cap.NodeTag,
+) context.Context {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(
+			RuntimeNameKey.String(runtimeName),
+			NodeTagKey.String(nodeTag.String()),
+		),
+	}
+	if elem, ok := t.finished[runtimeName]; ok {
+		prev, _ := elem.Value.(finishedRun)
+		opts = append(opts, trace.WithLinks(trace.Link{
+			SpanContext: prev.spanCtx,
+			Attributes:  []attribute.KeyValue{LinkKey.String("restart")},
+		}))
+		t.forgetFinished(elem)
+	}
+
+	// a node that starts without reporting the end of its previous run
+	if span, ok := t.active[runtimeName]; ok {
+		span.End()
+	}
+
+	ctx, span := t.tracer.Start(ctx, runtimeName, opts...)
+	t.active[runtimeName] = span
+	return ctx
+}
+
+// EventNotifier returns a cap.EventNotifier that ends the span of the nodes
+// that stop, and that records the other events of a node on its span
+//
+// Since: 0.3.0
+func (t *Tracer) EventNotifier() cap.EventNotifier {
+	return t.handleEvent
+}
+
+// handleEvent records the given event on the span of its node
+func (t *Tracer) handleEvent(ev cap.Event) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	runtimeName := ev.GetProcessRuntimeName()
+	span, ok := t.active[runtimeName]
+	if !ok {
+		return
+	}
+
+	switch ev.GetTag() {
+	case cap.ProcessFailed, cap.ProcessStartFailed:
+		if err := ev.Err(); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.AddEvent(ev.GetTag().String())
+		t.endSpan(runtimeName, ev.GetNodeTag(), span)
+
+	case cap.ProcessCompleted, cap.ProcessTerminated, cap.ProcessForceTerminated:
+		span.AddEvent(ev.GetTag().String())
+		t.endSpan(runtimeName, ev.GetNodeTag(), span)
+
+	default:
+		span.AddEvent(ev.GetTag().String(), trace.WithAttributes(eventAttributes(ev)...))
+	}
+}
+
+// endSpan ends the span of the node with the given runtime name, the span
+// context is kept to link a restart of the node
+func (t *Tracer) endSpan(runtimeName string, nodeTag cap.NodeTag, span trace.Span) {
+	span.End()
+	delete(t.active, runtimeName)
+
+	run := finishedRun{runtimeName: runtimeName, spanCtx: span.SpanContext()}
+	if elem, ok := t.finished[runtimeName]; ok {
+		elem.Value = run
+		t.finishedOrder.MoveToBack(elem)
+	} else {
+		t.finished[runtimeName] = t.finishedOrder.PushBack(run)
+	}
+
+	// the children of a supervisor that stopped start new runs when the
+	// supervisor is restarted, there is nothing to link
+	if nodeTag == cap.SupervisorT {
+		prefix := runtimeName + cap.NodeSepToken
+		for name, elem := range t.finished {
+			if strings.HasPrefix(name, prefix) {
+				t.forgetFinished(elem)
+			}
+		}
+	}
+
+	for t.finishedOrder.Len() > t.maxStoppedNodes {
+		t.forgetFinished(t.finishedOrder.Front())
+	}
+}
+
+// forgetFinished removes the given element of the last runs of stopped nodes
+func (t *Tracer) forgetFinished(elem *list.Element) {
+	run, _ := t.finishedOrder.Remove(elem).(finishedRun)
+	delete(t.finished, run.runtimeName)
+}
+
+// eventAttributes returns the span event attributes of the given event
+func eventAttributes(ev cap.Event) []attribute.KeyValue {
+	switch ev.GetTag() {
+	case cap.SupervisorRestarting,
+		cap.RestartToleranceIncremented,
+		cap.RestartToleranceWindowReset,
+		cap.ChildRestartScheduled:
+		return []attribute.KeyValue{
+			attribute.String("capataz.restart.source_runtime_name", ev.GetSourceRuntimeName()),
+			attribute.Int64("capataz.restart.count", int64(ev.GetRestartCount())),
+			attribute.Int64("capataz.restart.max_count", int64(ev.GetMaxRestartCount())),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,163 @@
+package otelcap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/capatazlib/go-capataz/cap"
+	"github.com/capatazlib/go-capataz/cap/otelcap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+// spansByName groups the given spans by name, in the order they ended
+func spansByName(spans tracetest.SpanStubs) map[string]tracetest.SpanStubs {
+	result := make(map[string]tracetest.SpanStubs)
+	for _, span := range spans {
+		result[span.Name] = append(result[span.Name], span)
+	}
+	return result
+}
+
+func TestTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := otelcap.NewTracer(tp)
+
+	failCh := make(chan struct{})
+
+	// a worker that creates a span of its own, it fails on its first run
+	runCount := 0
+	child1 := cap.NewWorker("child1", func(ctx context.Context) error {
+		runCount++
+		_, userSpan := tp.Tracer("user").Start(ctx, "user-span")
+		userSpan.End()
+
+		if runCount == 1 {
+			<-failCh
+			return errors.New("child1 failed")
+		}
+		<-ctx.Done()
+		return nil
+	})
+
+	subtree1 := cap.NewSupervisorSpec("subtree1", cap.WithNodes(child1))
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(cap.Subtree(subtree1)),
+		[]cap.Opt{cap.WithNodeContext(tracer.NodeContext)},
+		[]cap.EventNotifier{tracer.EventNotifier()},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+			close(failCh)
+			evIt.SkipTill(WorkerStarted("root/subtree1/child1"))
+		},
+	)
+	assert.NoError(t, err)
+
+	spans := spansByName(exporter.GetSpans())
+	assert.Len(t, spans["root"], 1)
+	assert.Len(t, spans["root/subtree1"], 1)
+	assert.Len(t, spans["root/subtree1/child1"], 2)
+	assert.Len(t, spans["user-span"], 2)
+
+	root := spans["root"][0]
+	subtree := spans["root/subtree1"][0]
+	firstRun := spans["root/subtree1/child1"][0]
+	secondRun := spans["root/subtree1/child1"][1]
+
+	// spans are nested by runtime name hierarchy
+	assert.False(t, root.Parent.IsValid())
+	assert.Equal(t, root.SpanContext.SpanID(), subtree.Parent.SpanID())
+	assert.Equal(t, subtree.SpanContext.SpanID(), firstRun.Parent.SpanID())
+	assert.Equal(t, subtree.SpanContext.SpanID(), secondRun.Parent.SpanID())
+	for _, span := range exporter.GetSpans() {
+		assert.Equal(t, root.SpanContext.TraceID(), span.SpanContext.TraceID())
+	}
+
+	// the spans of the worker logic are nested under the span of the run
+	assert.Equal(t, firstRun.SpanContext.SpanID(), spans["user-span"][0].Parent.SpanID())
+	assert.Equal(t, secondRun.SpanContext.SpanID(), spans["user-span"][1].Parent.SpanID())
+
+	// the failure is recorded on the span of the run
+	assert.Equal(t, codes.Error, firstRun.Status.Code)
+	assert.Equal(t, "child1 failed", firstRun.Status.Description)
+	assert.Equal(t, codes.Unset, secondRun.Status.Code)
+
+	// the restart is linked to the previous run
+	assert.Len(t, secondRun.Links, 1)
+	assert.Equal(t, firstRun.SpanContext, secondRun.Links[0].SpanContext)
+	assert.Equal(t, "restart", secondRun.Links[0].Attributes[0].Value.AsString())
+	assert.Empty(t, firstRun.Links)
+}
+
+func TestTracerMaxStoppedNodes(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	// the span of a stopped node is forgotten right away
+	tracer := otelcap.NewTracer(tp, otelcap.WithMaxStoppedNodes(0))
+
+	child1, failWorker1 := FailOnSignalWorker(1, "child1", cap.WithRestart(cap.Permanent))
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{cap.WithNodeContext(tracer.NodeContext)},
+		[]cap.EventNotifier{tracer.EventNotifier()},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+			failWorker1(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+	assert.NoError(t, err)
+
+	spans := spansByName(exporter.GetSpans())
+	assert.Len(t, spans["root/child1"], 2)
+
+	// the restart is not linked to the forgotten previous run
+	assert.Equal(t, codes.Error, spans["root/child1"][0].Status.Code)
+	assert.Empty(t, spans["root/child1"][1].Links)
+}
+
+func TestTracerWithoutNotifier(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := otelcap.NewTracer(tp)
+
+	var workerSpan trace.SpanContext
+	child1 := cap.NewWorker("child1", func(ctx context.Context) error {
+		workerSpan = trace.SpanFromContext(ctx).SpanContext()
+		<-ctx.Done()
+		return nil
+	})
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{cap.WithNodeContext(tracer.NodeContext)},
+		[]cap.EventNotifier{},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+		},
+	)
+	assert.NoError(t, err)
+
+	// the worker context carries the span of its run, the span does not end
+	// without the events of the supervision system
+	assert.True(t, workerSpan.IsValid())
+	assert.Empty(t, exporter.GetSpans())
+}
@@ -0,0 +1,172 @@
+//go:build go1.21
+
+// Package slogcap offers an EventNotifier that logs the events of a capataz
+// supervision system as structured records of a log/slog Logger.
+//
+// Each record has the event tag as its message, and the following attributes:
+//
+// * tag: the event tag
+//
+// * node_tag: the tag of the node that emitted the event (Worker or
+// Supervisor)
+//
+// * runtime_name: the runtime name of the node that emitted the event
+//
+// * duration: the duration of the event, when the event has one (see
+// Event.GetDuration)
+//
+// * labels: the labels of the node, when the node has some
+//
+// * source_runtime_name, restart_count and max_restart_count: the restart
+// bookkeeping of restart events
+//
+// * error: the error of the event, along with the key-values of capataz
+// errors (see ErrKVs); when the Logger has the debug level enabled, the
+// explanation of the error (see ExplainError) is included in the
+// error_explanation attribute
+//
+// This package requires Go 1.21 or later (it depends on log/slog).
+//
+// Since: 0.3.0
+package slogcap
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+
+	"github.com/capatazlib/go-capataz/cap"
+)
+
+// defaultLevels are the log levels of the events when no level is specified
+// with WithLevel, events that are not in this map are logged with
+// slog.LevelInfo
+var defaultLevels = map[cap.EventTag]slog.Level{
+	cap.ProcessStartFailed:           slog.LevelError,
+	cap.ProcessFailed:                slog.LevelError,
+	cap.ChildRestartToleranceReached: slog.LevelError,
+	cap.ProcessForceTerminated:       slog.LevelWarn,
+	cap.ProcessAbandoned:             slog.LevelWarn,
+	cap.SupervisorRestarting:         slog.LevelWarn,
+	cap.RestartToleranceIncremented:  slog.LevelDebug,
+	cap.RestartToleranceWindowReset:  slog.LevelDebug,
+	cap.ChildRestartScheduled:        slog.LevelDebug,
+}
+
+// notifierSettings contains the settings of the EventNotifier created with
+// NewNotifier
+type notifierSettings struct {
+	levels map[cap.EventTag]slog.Level
+}
+
+// Opt allows clients to tweak the behavior of the EventNotifier created with
+// NewNotifier
+//
+// Since: 0.3.0
+type Opt func(*notifierSettings)
+
+// WithLevel sets the log level of the events with the given tag
+//
+// Since: 0.3.0
+func WithLevel(tag cap.EventTag, level slog.Level) Opt {
+	return func(settings *notifierSettings) {
+		settings.levels[tag] = level
+	}
+}
+
+// NewNotifier creates a cap.EventNotifier that logs every event with the given
+// Logger. The level of each record depends on the tag of the event; failures
+// are logged with slog.LevelError, abandoned processes, forced terminations and
+// supervisor restarts with slog.LevelWarn, the restart tolerance bookkeeping
+// with slog.LevelDebug, and other events with slog.LevelInfo. Use WithLevel to
+// change the level of an event tag.
+//
+// Since: 0.3.0
+func NewNotifier(logger *slog.Logger, opts ...Opt) cap.EventNotifier {
+	settings := notifierSettings{
+		levels: make(map[cap.EventTag]slog.Level, len(defaultLevels)),
+	}
+	for tag, level := range defaultLevels {
+		settings.levels[tag] = level
+	}
+
+	for _, optFn := range opts {
+		optFn(&settings)
+	}
+
+	return func(ev cap.Event) {
+		ctx := context.Background()
+
+		level, ok := settings.levels[ev.GetTag()]
+		if !ok {
+			level = slog.LevelInfo
+		}
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+
+		logger.LogAttrs(ctx, level, ev.GetTag().String(), eventAttrs(ctx, logger, ev)...)
+	}
+}
+
+// eventAttrs returns the attributes of the log record of the given event
+func eventAttrs(ctx context.Context, logger *slog.Logger, ev cap.Event) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("tag", ev.GetTag().String()),
+		slog.String("node_tag", ev.GetNodeTag().String()),
+		slog.String("runtime_name", ev.GetProcessRuntimeName()),
+	}
+
+	if duration := ev.GetDuration(); duration > 0 {
+		attrs = append(attrs, slog.Duration("duration", duration))
+	}
+
+	if labels := ev.GetLabels(); len(labels) > 0 {
+		attrs = append(attrs, slog.Any("labels", labels))
+	}
+
+	switch ev.GetTag() {
+	case cap.SupervisorRestarting,
+		cap.RestartToleranceIncremented,
+		cap.RestartToleranceWindowReset,
+		cap.ChildRestartScheduled:
+		attrs = append(
+			attrs,
+			slog.String("source_runtime_name", ev.GetSourceRuntimeName()),
+			slog.Uint64("restart_count", uint64(ev.GetRestartCount())),
+			slog.Uint64("max_restart_count", uint64(ev.GetMaxRestartCount())),
+		)
+	}
+
+	if err := ev.Err(); err != nil {
+		attrs = append(attrs, errAttrs(ctx, logger, err)...)
+	}
+
+	return attrs
+}
+
+// errAttrs returns the attributes of the log record of an event with the given
+// error
+func errAttrs(ctx context.Context, logger *slog.Logger, err error) []slog.Attr {
+	attrs := []slog.Attr{slog.String("error", err.Error())}
+
+	var errKVs cap.ErrKVs
+	if errors.As(err, &errKVs) {
+		kvs := errKVs.KVs()
+		keys := make([]string, 0, len(kvs))
+		for key := range kvs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			attrs = append(attrs, slog.Any(key, kvs[key]))
+		}
+	}
+
+	if logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs, slog.String("error_explanation", cap.ExplainError(err)))
+	}
+
+	return attrs
+}
@@ -0,0 +1,174 @@
+//go:build go1.21
+
+package slogcap_test
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	"github.com/capatazlib/go-capataz/cap/slogcap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+// recordHandler is a slog.Handler that keeps the records it handles
+type recordHandler struct {
+	level   slog.Level
+	mux     *sync.Mutex
+	records *[]slog.Record
+}
+
+func newRecordHandler(level slog.Level) (recordHandler, func() []slog.Record) {
+	h := recordHandler{level: level, mux: &sync.Mutex{}, records: &[]slog.Record{}}
+	getRecords := func() []slog.Record {
+		h.mux.Lock()
+		defer h.mux.Unlock()
+		return append((*h.records)[:0:0], *h.records...)
+	}
+	return h, getRecords
+}
+
+func (h recordHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	*h.records = append(*h.records, r.Clone())
+	return nil
+}
+
+func (h recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h recordHandler) WithGroup(string) slog.Handler      { return h }
+
+// recordAttrs returns the attributes of the given record by key
+func recordAttrs(r slog.Record) map[string]slog.Value {
+	attrs := make(map[string]slog.Value)
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value
+		return true
+	})
+	return attrs
+}
+
+// findRecord returns the first record with the given message and runtime name
+func findRecord(
+	t *testing.T,
+	records []slog.Record,
+	msg, runtimeName string,
+) (slog.Record, map[string]slog.Value) {
+	for _, r := range records {
+		attrs := recordAttrs(r)
+		if r.Message == msg && attrs["runtime_name"].String() == runtimeName {
+			return r, attrs
+		}
+	}
+	assert.FailNow(t, "record not found", "msg: %s, runtime_name: %s", msg, runtimeName)
+	return slog.Record{}, nil
+}
+
+func TestNotifier(t *testing.T) {
+	handler, getRecords := newRecordHandler(slog.LevelDebug)
+	notifier := slogcap.NewNotifier(slog.New(handler))
+
+	child1, failWorker1 := FailOnSignalWorker(2, "child1", cap.WithRestart(cap.Permanent))
+	subtree1 := cap.NewSupervisorSpec(
+		"subtree1",
+		cap.WithNodes(child1),
+		cap.WithRestartTolerance(1, time.Minute),
+		cap.WithSupervisorLabels(map[string]string{"team": "core"}),
+	)
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(cap.Subtree(subtree1)),
+		[]cap.Opt{},
+		[]cap.EventNotifier{notifier},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/subtree1/child1"))
+
+			// the second failure surpasses the restart tolerance of subtree1
+			failWorker1(true /* done */)
+			evIt.SkipTill(SupervisorStarted("root/subtree1"))
+		},
+	)
+	assert.NoError(t, err)
+
+	records := getRecords()
+
+	r, attrs := findRecord(t, records, "ProcessStarted", "root/subtree1/child1")
+	assert.Equal(t, slog.LevelInfo, r.Level)
+	assert.Equal(t, "ProcessStarted", attrs["tag"].String())
+	assert.Equal(t, "Worker", attrs["node_tag"].String())
+	assert.Equal(t, slog.KindDuration, attrs["duration"].Kind())
+	assert.Equal(t, map[string]string{"team": "core"}, attrs["labels"].Any())
+
+	r, attrs = findRecord(t, records, "ProcessFailed", "root/subtree1/child1")
+	assert.Equal(t, slog.LevelError, r.Level)
+	assert.Equal(t, "Failing child (1 out of 2)", attrs["error"].String())
+	assert.Equal(t, "Failing child (1 out of 2)", attrs["error_explanation"].String())
+
+	r, attrs = findRecord(t, records, "SupervisorRestarting", "root/subtree1")
+	assert.Equal(t, slog.LevelWarn, r.Level)
+	assert.Equal(t, "root/subtree1/child1", attrs["source_runtime_name"].String())
+	assert.Equal(t, uint64(1), attrs["restart_count"].Uint64())
+
+	// the key-values of capataz errors are included on the record
+	r, attrs = findRecord(t, records, "ProcessFailed", "root/subtree1")
+	assert.Equal(t, slog.LevelError, r.Level)
+	assert.Equal(t, "Supervisor", attrs["node_tag"].String())
+	assert.Equal(t, "root/subtree1", attrs["supervisor.name"].String())
+	assert.Equal(t, "root/subtree1/child1", attrs["supervisor.restart.node.name"].String())
+	assert.Contains(t, attrs["error_explanation"].String(), "restart tolerance surpassed")
+}
+
+func TestNotifierLevels(t *testing.T) {
+	handler, getRecords := newRecordHandler(slog.LevelInfo)
+	notifier := slogcap.NewNotifier(
+		slog.New(handler),
+		slogcap.WithLevel(cap.ProcessStarted, slog.LevelDebug),
+		slogcap.WithLevel(cap.ProcessFailed, slog.LevelWarn),
+	)
+
+	child1, failWorker1 := FailOnSignalWorker(1, "child1", cap.WithRestart(cap.Permanent))
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		[]cap.EventNotifier{notifier},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+			failWorker1(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+	assert.NoError(t, err)
+
+	records := getRecords()
+	for _, r := range records {
+		assert.NotEqual(t, "ProcessStarted", r.Message)
+	}
+
+	r, attrs := findRecord(t, records, "ProcessFailed", "root/child1")
+	assert.Equal(t, slog.LevelWarn, r.Level)
+	// the error explanation is only included when the debug level is enabled
+	_, ok := attrs["error_explanation"]
+	assert.False(t, ok)
+
+	r, _ = findRecord(t, records, "ProcessTerminated", "root")
+	assert.Equal(t, slog.LevelInfo, r.Level)
+}
@@ -1,6 +1,7 @@
 package cap
 
 import (
+	"github.com/capatazlib/go-capataz/internal/c"
 	"github.com/capatazlib/go-capataz/internal/s"
 )
 
@@ -44,6 +45,13 @@ var OneForOne = s.OneForOne
 // Since: 0.2.0
 var OneForAll = s.OneForAll
 
+// RestForOne is an Strategy that tells the Supervisor to restart a failed
+// child process and all the siblings that were started after it, following the
+// supervisor's start Order
+//
+// Since: 0.3.0
+var RestForOne = s.RestForOne
+
 // CleanupResourcesFn is a function that cleans up resources that were
 // allocated in a BuildNodesFn function.
 //
@@ -209,12 +217,17 @@ var WithOrder = WithStartOrder
 //
 // * OneForOne -- Only restart the failing child
 //
-// * OneForAll (Not Implemented Yet) -- Restart the failing child and all its
-// siblings[*]
+// * OneForAll -- Restart the failing child and all its siblings[*]
+//
+// * RestForOne -- Restart the failing child and all the siblings that were
+// started after it (as specified by WithStartOrder)[**]
 //
 // [*] This option may come handy when all the other siblings depend on one another
 // to work correctly.
 //
+// [**] This option may come handy when siblings started later depend on the
+// siblings that were started before them (e.g. stages of a pipeline).
+//
 // Since: 0.0.0
 var WithStrategy = s.WithStrategy
 
@@ -253,6 +266,113 @@ var WithNodes = s.WithNodes
 // Since: 0.1.0
 var WithRestartTolerance = s.WithRestartTolerance
 
+// MaxChildrenPolicy specifies what happens when a supervisor is asked to spawn
+// a child and it already supervises the maximum number of children (see
+// WithMaxChildren)
+//
+// Since: 0.3.0
+type MaxChildrenPolicy = s.MaxChildrenPolicy
+
+// RejectSpawn is a MaxChildrenPolicy that makes the spawn call return a
+// MaxChildrenReachedError
+//
+// Since: 0.3.0
+var RejectSpawn = s.RejectSpawn
+
+// WaitForSlot is a MaxChildrenPolicy that makes the spawn call block until one
+// of the children of the supervisor finishes, or until the context of the spawn
+// call is done
+//
+// Since: 0.3.0
+var WaitForSlot = s.WaitForSlot
+
+// WithMaxChildren is an Opt that specifies the maximum number of children a
+// DynSupervisor (or the sub-tree of a NewDynSubtree) may supervise at the same
+// time. Children waiting for a delayed restart count against this limit.
+//
+// The given MaxChildrenPolicy specifies what happens when a spawn call is done
+// and the limit is reached:
+//
+// * RejectSpawn -- The spawn call returns a MaxChildrenReachedError
+//
+// * WaitForSlot -- The spawn call blocks until a child finishes, or until the
+// context of the spawn call is done
+//
+// Example
+//
+//   // Spawn at most 1000 connection workers, wait for a free slot otherwise
+//   cap.NewDynSupervisor(ctx, "connections", cap.WithMaxChildren(1000, cap.WaitForSlot))
+//
+// Since: 0.3.0
+var WithMaxChildren = s.WithMaxChildren
+
+// WithWaitForReadiness is an Opt that specifies the supervisor must wait for
+// each child node to be ready before starting the next one (as specified by
+// WithStartOrder). Workers created with the WithReadiness option are ready once
+// they call NotifyReady, other nodes are ready once they start.
+//
+// The supervisor waits at most the given timeout for each worker; if a worker
+// is not ready on time, or if it finishes before it reports it is ready, the
+// supervisor continues starting the next child nodes. The ProcessReady event of
+// a worker that was late is reported once the worker is ready, and a worker
+// that finished is restarted as usual. A timeout lower or equal than zero
+// disables the wait. The wait only happens when the supervisor starts; restarts
+// (with any restart strategy) never wait for the readiness of children.
+//
+// Example
+//
+//   // Start the api worker once the cache worker is warm
+//   cap.NewSupervisorSpec("root",
+//     cap.WithNodes(
+//       cap.NewWorker("cache", cacheFn, cap.WithReadiness()),
+//       cap.NewWorker("api", apiFn),
+//     ),
+//     cap.WithWaitForReadiness(10*time.Second),
+//   )
+//
+// Since: 0.3.0
+var WithWaitForReadiness = s.WithWaitForReadiness
+
+// WithSupervisorShutdown is an Opt that specifies how much time the supervisor
+// waits for all its children nodes to stop executing when it gets terminated.
+// When the supervisor runs as a sub-tree, its parent supervisor waits for it
+// using the same Shutdown value, unless a WithShutdown option is given to
+// Subtree.
+//
+// The default value is Indefinitely. When a Timeout is given, children that do
+// not stop on time are reported in a SupervisorTerminationError. The resource
+// cleanup function of the supervisor is not bound by the timeout.
+//
+// Example:
+//
+//   cap.NewSupervisorSpec(
+//     "pipeline",
+//     cap.WithNodes(...),
+//     // wait for in-flight messages to be flushed
+//     cap.WithSupervisorShutdown(cap.Timeout(time.Minute)),
+//   )
+//
+// Since: 0.3.0
+var WithSupervisorShutdown = s.WithSupervisorShutdown
+
+// WithShutdownTimeout is an Opt that specifies the maximum duration the
+// supervisor waits for all its children nodes to stop executing when it gets
+// terminated. It is equivalent to WithSupervisorShutdown(Timeout(d)).
+//
+// Since: 0.3.0
+var WithShutdownTimeout = s.WithShutdownTimeout
+
+// WithSupervisorPanicPolicy is an Opt that specifies what happens when a child
+// node of the supervisor panics (see PanicPolicy). Workers that specify a
+// panic policy of their own with WithPanicPolicy, or that do not capture panics
+// with WithCapturePanic(false), are not affected by this option; workers created
+// with WithCapturePanic(true) use the panic policy of the supervisor. Sub-trees
+// that do not specify a panic policy inherit the one of their parent
+// supervisor.
+//
+// Since: 0.3.0
+var WithSupervisorPanicPolicy = s.WithSupervisorPanicPolicy
+
 // Subtree transforms SupervisorSpec into a Node. This function allows you to
 // insert a black-box sub-system into a bigger supervised system.
 //
@@ -279,6 +399,12 @@ var WithRestartTolerance = s.WithRestartTolerance
 // Since: 0.0.0
 var Subtree = s.Subtree
 
+// NodeSepToken is the token use to separate sub-trees and child node names in
+// the runtime names of the supervision tree
+//
+// Since: 0.3.0
+const NodeSepToken = s.NodeSepToken
+
 // DynSupervisor is a supervisor that can spawn workers in a procedural way.
 //
 // Since: 0.0.0
@@ -306,14 +432,42 @@ type DynSupervisor = s.DynSupervisor
 // * In case of a hard crash and following restart, it will start with an empty
 //   list of children
 //
+// Restarts of spawned children
+//
+// Spawned children are supervised with the restart strategy (WithStrategy) and
+// the restart tolerance (WithRestartTolerance) of the DynSupervisor. When the
+// restart tolerance is surpassed, the DynSupervisor fails the same way a static
+// Supervisor does. Children that are not going to be restarted (e.g. a
+// Temporary child that finished) are removed from the DynSupervisor.
+//
 // Since: 0.0.0
 var NewDynSupervisor = s.NewDynSupervisor
 
+// NewSimpleOneForOneSupervisor creates a DynSupervisor in which every child is
+// spawned from the given template node with the SpawnFromTemplate method. This
+// is the equivalent of Erlang's simple_one_for_one supervisor; the children of
+// this DynSupervisor are independent from each other, so they always get
+// restarted with the OneForOne strategy.
+//
+// Since: 0.3.0
+var NewSimpleOneForOneSupervisor = s.NewSimpleOneForOneSupervisor
+
 // Spawner is a builder type that can spawn other workers
 //
 // since: 0.2.0
 type Spawner = s.Spawner
 
+// ContextSpawner is a Spawner that can give up on a spawn request when the
+// given context is done (e.g. while a WithMaxChildren limit is reached). The
+// Spawner given to a NewDynSubtree worker implements it:
+//
+//	if cs, ok := spawner.(cap.ContextSpawner); ok {
+//	  cancelFn, err = cs.SpawnContext(ctx, node)
+//	}
+//
+// Since: 0.3.0
+type ContextSpawner = s.ContextSpawner
+
 // NewDynSubtree builds a worker that has receives a Spawner that allows it to
 // create more child workers dynamically in a sub-tree.
 //
@@ -338,3 +492,26 @@ var NewDynSubtreeWithNotifyStart = s.NewDynSubtreeWithNotifyStart
 //
 // Since: 0.0.0
 type Supervisor = s.Supervisor
+
+// WithSupervisorLabels is an Opt that specifies labels that are attached to
+// the events the supervision system emits for the supervisor (see
+// Event.GetLabels). Children nodes and sub-trees inherit these labels; the
+// labels given to a node with WithLabels win over the inherited ones.
+//
+// Since: 0.3.0
+var WithSupervisorLabels = s.WithSupervisorLabels
+
+// NodeContextFn is a function that gets called every time a node of the
+// supervision tree starts, it returns the context the node runs with
+//
+// Since: 0.3.0
+type NodeContextFn = c.NodeContextFn
+
+// WithNodeContext is an Opt that specifies a function that gets called every
+// time a node of the supervision tree starts, including the supervisor itself.
+// The context returned by the function is the context the node runs with; this
+// is useful to attach values to the context of every worker (e.g. tracing
+// spans). Sub-trees use the function of their parent supervisor.
+//
+// Since: 0.3.0
+var WithNodeContext = s.WithNodeContext
@@ -69,6 +69,70 @@ var Indefinitely = c.Indefinitely
 // Since: 0.0.0
 var Timeout = c.Timeout
 
+// Graceful is a Shutdown function that returns a value that specifies a
+// two-phase shutdown: the supervisor first requests the worker goroutine to
+// stop (see ShutdownRequested) and waits up to the given grace period; if the
+// worker goroutine is still running after the grace period, its context is
+// canceled and the supervisor waits for it as specified by the given Shutdown
+// value. You can specify this option using the WithShutdown function.
+//
+// This shutdown allows workers to finish their in-flight work (e.g. requests)
+// before the resources bound to their context are released.
+//
+// Example:
+//
+//   cap.NewWorker(
+//     "api",
+//     func(ctx context.Context) error {
+//       for {
+//         select {
+//         case <-cap.ShutdownRequested(ctx):
+//           // stop accepting requests and wait for in-flight ones
+//           return server.Shutdown(ctx)
+//         case req := <-reqCh:
+//           go handle(ctx, req)
+//         }
+//       }
+//     },
+//     cap.WithShutdown(cap.Graceful(10*time.Second, cap.Timeout(time.Second))),
+//   )
+//
+// Since: 0.3.0
+var Graceful = c.Graceful
+
+// ShutdownRequested returns a channel that gets closed when the parent
+// supervisor requests the worker running with the given context to stop, or
+// when the context of the worker is done. For workers without a Graceful
+// shutdown, it returns ctx.Done().
+//
+// Since: 0.3.0
+var ShutdownRequested = c.ShutdownRequested
+
+// AbandonedProcess is a record of a process goroutine that did not stop within
+// its shutdown timeout, and that is still running in the background
+//
+// Since: 0.3.0
+type AbandonedProcess = c.AbandonedChild
+
+// AbandonedProcesses returns all the process goroutines that did not stop
+// within their shutdown timeout and that are still running, sorted by the time
+// their parent supervisor gave up on them. Processes are removed from this
+// list once their goroutine finishes, which is reported with a
+// ProcessAbandonedExited event when their parent supervisor is still running.
+//
+// Since: 0.3.0
+var AbandonedProcesses = c.AbandonedChildren
+
+// WithCaptureAbandonedStack is a WorkerOpt that specifies the stack of the
+// worker goroutine must be captured when the parent supervisor gives up on its
+// termination; the stack is available with AbandonedProcess.GetStack. The
+// worker goroutine runs with a pprof label to find its stack, capturing the
+// stack requires a dump of all the running goroutines, use this option for
+// debugging purposes.
+//
+// Since: 0.3.0
+var WithCaptureAbandonedStack = c.WithCaptureAbandonedStack
+
 // NodeTag specifies the type of node that is running. This is a closed set
 // given we will only support workers and supervisors
 //
@@ -126,6 +190,39 @@ var WithShutdown = c.WithShutdown
 // Since: 0.0.0
 var WithCapturePanic = c.WithCapturePanic
 
+// PanicPolicy specifies what happens when a worker goroutine panics
+//
+// Since: 0.3.0
+type PanicPolicy = c.PanicPolicy
+
+// RestartOnPanic is a PanicPolicy that specifies the panic is reported as an
+// error (see PanicError), and the worker is restarted as with any other error.
+// This is the default policy.
+//
+// Since: 0.3.0
+var RestartOnPanic = c.RestartOnPanic
+
+// EscalateOnPanic is a PanicPolicy that specifies the panic is reported as an
+// error (see PanicError), and the parent supervisor fails right away without
+// restarting the worker, regardless of restart tolerances. Use this policy
+// when a panic may leave shared state corrupted and restarting is not safe.
+//
+// Since: 0.3.0
+var EscalateOnPanic = c.EscalateOnPanic
+
+// CrashOnPanic is a PanicPolicy that specifies the panic is not captured,
+// crashing the program. It is equivalent to WithCapturePanic(false).
+//
+// Since: 0.3.0
+var CrashOnPanic = c.CrashOnPanic
+
+// WithPanicPolicy is a WorkerOpt that specifies what happens when the worker
+// panics; it overrides the panic policy of the parent supervisor (see
+// WithSupervisorPanicPolicy).
+//
+// Since: 0.3.0
+var WithPanicPolicy = c.WithPanicPolicy
+
 // WithTag is a WorkerOpt that sets the given NodeTag on Worker.
 //
 // Do not use this function if you are not extending capataz' API.
@@ -133,14 +230,148 @@ var WithCapturePanic = c.WithCapturePanic
 // Since: 0.0.0
 var WithTag = c.WithTag
 
+// RestartBackoff specifies the delay a supervisor waits before restarting a
+// worker. Check the WithRestartBackoff documentation for more details.
+//
+// Since: 0.3.0
+type RestartBackoff = c.RestartBackoff
+
+// WithRestartBackoff is a WorkerOpt that specifies how much time the parent
+// supervisor should wait before restarting this worker.
+//
+// The first restart is delayed by the initial duration, and every consecutive
+// restart multiplies the previous delay by the given multiplier, up to the max
+// duration. The jitter (a value between 0 and 1) reduces each delay by a random
+// fraction of it. Once the worker stays up for longer than the max duration,
+// the delay is reset to the initial duration.
+//
+// While a restart is delayed, the supervisor keeps monitoring its other
+// children. The failure that caused the restart is accounted on the supervisor
+// restart tolerance right away.
+//
+// Example
+//
+//   // Wait 100ms, 200ms, 400ms, ... up to 10s between restarts, reducing each
+//   // delay up to a 20%
+//   cap.WithRestartBackoff(100 * time.Millisecond, 10 * time.Second, 2, 0.2)
+//
+// Since: 0.3.0
+var WithRestartBackoff = c.WithRestartBackoff
+
 // WithTolerance is a WorkerOpt that specifies how many errors the supervisor
-// should be willing to tolerate before giving up restarting and fail.
+// should be willing to tolerate on this worker before giving up restarting it.
 //
-// Deprecated: Use WithRestartTolerance instead.
+// Errors of this worker are accounted on this tolerance first, and then on the
+// restart tolerance of the supervisor (see WithRestartTolerance). When this
+// tolerance is surpassed, the supervisor emits a ChildRestartToleranceReached
+// event and executes the ToleranceAction specified with WithToleranceAction;
+// in this case, the supervisor restart tolerance is not modified.
+//
+// Example
+//
+//   // Tolerate 3 errors every 10 seconds on this worker, if there are more,
+//   // make the supervisor fail
+//   cap.NewWorker("flaky", flakyFn,
+//     cap.WithTolerance(3, 10 * time.Second),
+//     cap.WithToleranceAction(cap.Escalate),
+//   )
 //
 // Since: 0.0.0
 var WithTolerance = c.WithTolerance
 
+// ToleranceAction specifies what a supervisor does with a worker that
+// surpassed its own restart tolerance
+//
+// Since: 0.3.0
+type ToleranceAction = c.ToleranceAction
+
+// GiveUp is a ToleranceAction that specifies the supervisor must stop
+// restarting the worker; the supervisor and the worker's siblings keep
+// running. This is the default ToleranceAction.
+//
+// Since: 0.3.0
+var GiveUp = c.GiveUp
+
+// Escalate is a ToleranceAction that specifies the supervisor must fail
+// (regardless of its own restart tolerance), delegating the error handling to
+// its parent supervisor.
+//
+// Since: 0.3.0
+var Escalate = c.Escalate
+
+// WithToleranceAction is a WorkerOpt that specifies what the supervisor does
+// when this worker surpasses the restart tolerance given with WithTolerance.
+//
+// Since: 0.3.0
+var WithToleranceAction = c.WithToleranceAction
+
+// WithLivenessProbe is a WorkerOpt that specifies a probe the supervisor runs
+// every interval while the worker is running. When the probe returns an error,
+// or when it does not return within the given timeout, the supervisor cancels
+// the worker, emits a ProcessFailed event with a LivenessTimeoutError and
+// restarts the worker according to its Restart value.
+//
+// If the worker does not respect the cancelation of its context, the failure
+// is reported once the worker's Shutdown timeout is reached, and the hung
+// goroutine is left running while the supervisor restarts the worker; the hung
+// goroutine is reported by AbandonedProcesses until it finishes.
+//
+// Example
+//
+//   // Check every second that the worker's connection is alive
+//   cap.NewWorker("consumer", consumerFn,
+//     cap.WithLivenessProbe(time.Second, 500 * time.Millisecond, conn.Ping),
+//   )
+//
+// Since: 0.3.0
+var WithLivenessProbe = c.WithLivenessProbe
+
+// WithHeartbeat is a WorkerOpt that specifies the worker must call Heartbeat
+// with its context at least once every timeout. When the worker misses a
+// heartbeat, the supervisor handles it the same way it handles a failed
+// liveness probe (see WithLivenessProbe).
+//
+// Since: 0.3.0
+var WithHeartbeat = c.WithHeartbeat
+
+// Heartbeat reports to the supervisor that the worker running with the given
+// context is making progress. This function is a no-op when the worker was not
+// created with the WithHeartbeat option.
+//
+// Since: 0.3.0
+var Heartbeat = c.Heartbeat
+
+// WithStartTimeout is a WorkerOpt that specifies how much time the supervisor
+// waits for a worker created with NewWorkerWithNotifyStart to call its
+// NotifyStartFn. When the timeout is reached, the worker's context is canceled
+// and the worker fails to start with a StartTimeoutError; this error is
+// reported on a ProcessStartFailed event and inside a SupervisorStartError.
+//
+// Without this option, the supervisor waits indefinitely for the worker to
+// start.
+//
+// Since: 0.3.0
+var WithStartTimeout = c.WithStartTimeout
+
+// WithReadiness is a WorkerOpt that specifies the worker reports it is ready to
+// do its work some time after it started, by calling NotifyReady with its
+// context. This allows a worker to warm up (e.g. fill a cache) without
+// blocking the start of its siblings.
+//
+// The supervisor emits a ProcessReady event when the worker is ready; until
+// then, the worker is reported on the GetNotReadyProcesses method of a
+// HealthReport.
+//
+// Since: 0.3.0
+var WithReadiness = c.WithReadiness
+
+// NotifyReady reports to the supervisor that the worker running with the given
+// context is ready to do its work. This function is a no-op when the worker was
+// not created with the WithReadiness option.
+//
+// Since: 0.3.0
+var NotifyReady = c.NotifyReady
+
 // GetWorkerName returns the runtime name of a supervised goroutine by plucking it
 // up from the given context.
 //
@@ -208,3 +439,12 @@ var NewWorker = s.NewWorker
 //
 // Since: 0.0.0
 var NewWorkerWithNotifyStart = s.NewWorkerWithNotifyStart
+
+// WithLabels is a WorkerOpt that specifies labels that are attached to the
+// events the supervision system emits for the worker (see Event.GetLabels).
+// The labels are merged with the labels inherited from the parent supervisor.
+// This option can also be given to Subtree, in which case the labels are
+// inherited by all the nodes of the sub-tree.
+//
+// Since: 0.3.0
+var WithLabels = c.WithLabels
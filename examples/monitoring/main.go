@@ -36,6 +36,7 @@ func stopOnSignal(sup cap.Supervisor) {
 func main() {
 	// Setup the logging mechanisms
 	log, logEventNotifier := newLogEventNotifier()
+	promEventNotifier := newPromEventNotifier()
 
 	// Build a supervision tree that runs the Prometheus HTTP server
 	prometheusSpec := newPrometheusSpec("prometheus", metricsHTTPAddr)
@@ -5,30 +5,19 @@ import (
 	"net/http"
 
 	"github.com/capatazlib/go-capataz/cap"
+	"github.com/capatazlib/go-capataz/cap/metrics"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-var (
-	eventGauge = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "supervisor_event_gauge",
-		},
-		[]string{"type", "process_name"},
-	)
-)
-
 ////////////////////////////////////////////////////////////////////////////////
 
-// This is an cap.EventNotifier that registers capataz' Events to prometheus
-func promEventNotifier(ev cap.Event) {
-	gauge := eventGauge.WithLabelValues(ev.GetTag().String(), ev.GetProcessRuntimeName())
-	if ev.GetTag() == cap.ProcessStarted {
-		gauge.Inc()
-	} else {
-		gauge.Dec()
-	}
+// newPromEventNotifier returns a cap.EventNotifier that registers capataz'
+// Events to prometheus
+func newPromEventNotifier() cap.EventNotifier {
+	collector := metrics.NewCollector()
+	prometheus.MustRegister(collector)
+	return collector.EventNotifier()
 }
 
 ////////////////////////////////////////////////////////////////////////////////
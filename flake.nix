@@ -4,9 +4,9 @@
   # nixConfig.bash-prompt-suffix = "(flake)";
 
   inputs = {
-    nixpkgs.url = "github:NixOS/nixpkgs/nixpkgs-unstable";
+    nixpkgs.url = "github:NixOS/nixpkgs/nixos-25.05";
     flake-utils.url = "github:numtide/flake-utils";
-    gomod2nix.url = "github:tweag/gomod2nix";
+    gomod2nix.url = "github:nix-community/gomod2nix";
     gomod2nix.inputs.nixpkgs.follows = "nixpkgs";
   };
 
@@ -16,9 +16,9 @@
       let
         pkgs = nixpkgs.legacyPackages.${system}.appendOverlays [
           self.overlay.${system}
-          gomod2nix.overlay.${system}
+          gomod2nix.overlays.default
         ];
-        go = pkgs.go_1_16;
+        go = pkgs.go_1_24;
       in
         {
 
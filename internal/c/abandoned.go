@@ -0,0 +1,164 @@
+package c
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ShutdownTimeoutError is the error reported when a child does not stop
+// executing within the duration specified by its Shutdown value. The parent
+// supervisor abandons the child goroutine, which may keep running in the
+// background (see AbandonedProcesses).
+type ShutdownTimeoutError struct {
+	nodeName string
+	timeout  time.Duration
+}
+
+// Error returns an error message
+func (err *ShutdownTimeoutError) Error() string {
+	return "child shutdown timeout"
+}
+
+// GetNodeName returns the runtime name of the child that got abandoned
+func (err *ShutdownTimeoutError) GetNodeName() string {
+	return err.nodeName
+}
+
+// GetTimeout returns the duration the parent supervisor waited for the child
+func (err *ShutdownTimeoutError) GetTimeout() time.Duration {
+	return err.timeout
+}
+
+// AbandonedChild is a record of a child goroutine that did not stop executing
+// within its shutdown timeout, and that is still running in the background.
+type AbandonedChild struct {
+	runtimeName string
+	abandonedAt time.Time
+	stack       []byte
+}
+
+// GetRuntimeName returns the runtime name of the abandoned child
+func (ac AbandonedChild) GetRuntimeName() string {
+	return ac.runtimeName
+}
+
+// GetAbandonedAt returns the timestamp of when the parent supervisor gave up on
+// the child
+func (ac AbandonedChild) GetAbandonedAt() time.Time {
+	return ac.abandonedAt
+}
+
+// GetStack returns the stack of the child goroutine (and of the goroutines it
+// spawned) at the time it got abandoned, in the format of the goroutine
+// profile; it is nil when the child was not created with the
+// WithCaptureAbandonedStack option.
+func (ac AbandonedChild) GetStack() []byte {
+	return ac.stack
+}
+
+// abandonedRegistry keeps track of all the child goroutines that are running
+// after their parent supervisor gave up on them
+var abandonedRegistry = struct {
+	mu      sync.Mutex
+	nextID  uint64
+	entries map[uint64]AbandonedChild
+}{
+	entries: make(map[uint64]AbandonedChild),
+}
+
+// registerAbandoned adds a child to the abandoned registry, the child is
+// removed from the registry once the given doneCh is closed
+func registerAbandoned(
+	runtimeName string,
+	runLabel string,
+	doneCh <-chan struct{},
+) {
+	entry := AbandonedChild{
+		runtimeName: runtimeName,
+		abandonedAt: time.Now(),
+	}
+	if runLabel != "" {
+		entry.stack = goroutineStack(runLabel)
+	}
+
+	abandonedRegistry.mu.Lock()
+	abandonedRegistry.nextID++
+	id := abandonedRegistry.nextID
+	abandonedRegistry.entries[id] = entry
+	abandonedRegistry.mu.Unlock()
+
+	go func() {
+		<-doneCh
+		abandonedRegistry.mu.Lock()
+		delete(abandonedRegistry.entries, id)
+		abandonedRegistry.mu.Unlock()
+	}()
+}
+
+// AbandonedChildren returns all the child goroutines that did not stop within
+// their shutdown timeout and that are still running, sorted by the time they
+// got abandoned.
+func AbandonedChildren() []AbandonedChild {
+	abandonedRegistry.mu.Lock()
+	acc := make([]AbandonedChild, 0, len(abandonedRegistry.entries))
+	for _, entry := range abandonedRegistry.entries {
+		acc = append(acc, entry)
+	}
+	abandonedRegistry.mu.Unlock()
+
+	sort.Slice(acc, func(i, j int) bool {
+		return acc[i].abandonedAt.Before(acc[j].abandonedAt)
+	})
+	return acc
+}
+
+// abandonedRunLabel is the pprof label that identifies the goroutines of a
+// child run, it is only set on children created with the
+// WithCaptureAbandonedStack option
+const abandonedRunLabel = "capataz.run"
+
+// abandonedRunID is used to generate the values of the abandonedRunLabel
+var abandonedRunID uint64
+
+// setAbandonedRunLabel adds a unique abandonedRunLabel to the given context, the
+// returned label value allows to find the stack of the goroutines that run
+// with this context (see pprof.SetGoroutineLabels).
+func setAbandonedRunLabel(ctx context.Context) (context.Context, string) {
+	runLabel := strconv.FormatUint(atomic.AddUint64(&abandonedRunID, 1), 10)
+	return pprof.WithLabels(ctx, pprof.Labels(abandonedRunLabel, runLabel)), runLabel
+}
+
+// goroutineStack returns the stacks of the goroutines that have the given value
+// on their abandonedRunLabel (as reported by the goroutine profile), it returns
+// nil if there is no such goroutine.
+func goroutineStack(runLabel string) []byte {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil
+	}
+
+	// every record of the profile is separated by a blank line, and it has a
+	// line with the labels of the goroutines it represents
+	labelLine := []byte(fmt.Sprintf("%q:%q", abandonedRunLabel, runLabel))
+	var acc [][]byte
+	for _, record := range bytes.Split(buf.Bytes(), []byte("\n\n")) {
+		for _, line := range bytes.Split(record, []byte("\n")) {
+			if bytes.HasPrefix(line, []byte("# labels: ")) &&
+				bytes.Contains(line, labelLine) {
+				acc = append(acc, record)
+				break
+			}
+		}
+	}
+	if len(acc) == 0 {
+		return nil
+	}
+	return bytes.Join(acc, []byte("\n\n"))
+}
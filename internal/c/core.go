@@ -1,5 +1,7 @@
 package c
 
+import "time"
+
 ////////////////////////////////////////////////////////////////////////////////
 
 // GetName returns the specified name for a Child Spec
@@ -12,6 +14,34 @@ func (chSpec ChildSpec) GetName() string {
 // second return value is non-nil when the child fails to terminate. If the
 // first return value is true, the second return value will always be nil.
 func (ch Child) Terminate() (bool, error) {
+	return ch.TerminateWithin(time.Time{})
+}
+
+// TerminateWithin is like Terminate, but it waits for the child to stop
+// executing until the given deadline at most, even when the Shutdown value of
+// the child allows a longer wait. A zero deadline behaves like Terminate.
+func (ch Child) TerminateWithin(deadline time.Time) (bool, error) {
+	// on Graceful shutdowns, the child gets a chance to stop on its own before
+	// its context is canceled
+	if grace := ch.spec.Shutdown.grace; grace > 0 {
+		ch.stop()
+		if untilDeadline := time.Until(deadline); !deadline.IsZero() && untilDeadline < grace {
+			grace = untilDeadline
+			if grace < 0 {
+				grace = 0
+			}
+		}
+		if stopped, isFirstTermination, err := ch.waitGracePeriod(grace); stopped {
+			return isFirstTermination, err
+		}
+	}
 	ch.cancel()
-	return ch.wait(ch.spec.Shutdown)
+	return ch.wait(ch.spec.Shutdown.within(deadline))
+}
+
+// Done returns a channel that gets closed when the goroutine of this child
+// finishes its execution. A child that was abandoned by its parent supervisor
+// (see ShutdownTimeoutError) may finish some time after it got terminated.
+func (ch Child) Done() <-chan struct{} {
+	return ch.doneCh
 }
@@ -0,0 +1,48 @@
+package c
+
+import (
+	"context"
+	"time"
+)
+
+// stopKey is an internal representation of the stop request channel in the
+// worker context.
+var stopKey capatazKey = "__capataz.node.stop__"
+
+// setStopCh allows to add the stop request channel of a child to a context.
+// Children without a Graceful shutdown get a nil channel, so that they do not
+// observe the stop requests of a parent worker.
+func setStopCh(ctx context.Context, stopCh <-chan struct{}) context.Context {
+	return context.WithValue(ctx, stopKey, stopCh)
+}
+
+// ShutdownRequested returns a channel that gets closed when the parent
+// supervisor requests the worker running with the given context to stop. The
+// channel also gets closed when the context of the worker is done.
+//
+// Workers created with a Graceful shutdown should finish their in-flight work
+// and return once this channel is closed; their context is canceled after the
+// grace period. For other workers, this function returns ctx.Done().
+func ShutdownRequested(ctx context.Context) <-chan struct{} {
+	if stopCh, _ := ctx.Value(stopKey).(<-chan struct{}); stopCh != nil {
+		return stopCh
+	}
+	return ctx.Done()
+}
+
+// waitGracePeriod waits for a child that got a stop request to stop on its
+// own. The first return value is false when the child did not stop within the
+// given grace period, the other return values are the same as the ones of
+// Terminate.
+func (ch Child) waitGracePeriod(grace time.Duration) (bool, bool, error) {
+	select {
+	case childNotification, ok := <-ch.terminateCh:
+		if !ok {
+			return true, false, nil
+		}
+		// A child may have terminated with an error
+		return true, true, childNotification.Unwrap()
+	case <-time.After(grace):
+		return false, false, nil
+	}
+}
@@ -0,0 +1,18 @@
+package c
+
+// MergeLabels returns a new map with the entries of both the given parent and
+// child labels; on conflicting keys, the values of child win. It returns nil
+// when both maps are empty.
+func MergeLabels(parent, child map[string]string) map[string]string {
+	if len(parent) == 0 && len(child) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(parent)+len(child))
+	for k, v := range parent {
+		result[k] = v
+	}
+	for k, v := range child {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,182 @@
+package c
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// heartbeatKey is an internal representation of the heartbeat channel in the
+// worker context.
+var heartbeatKey capatazKey = "__capataz.node.heartbeat__"
+
+// setHeartbeatCh allows to add the heartbeat channel of a child to a context.
+// Children without heartbeat liveness get a nil channel, so that they do not
+// report heartbeats of a parent worker.
+func setHeartbeatCh(ctx context.Context, beatCh chan struct{}) context.Context {
+	return context.WithValue(ctx, heartbeatKey, beatCh)
+}
+
+// Heartbeat reports to the parent supervisor that the worker running with the
+// given context is making progress. This function is a no-op when the worker
+// was not created with the WithHeartbeat option.
+func Heartbeat(ctx context.Context) {
+	beatCh, _ := ctx.Value(heartbeatKey).(chan struct{})
+	// do not block the worker when there is a pending heartbeat
+	select {
+	case beatCh <- struct{}{}:
+	default:
+	}
+}
+
+// Liveness specifies how the parent supervisor checks a child goroutine is
+// making progress while it is running.
+type Liveness struct {
+	interval time.Duration
+	timeout  time.Duration
+	probe    func(context.Context) error
+}
+
+// isHeartbeat indicates if this Liveness expects heartbeats from the child
+// rather than running a probe
+func (l Liveness) isHeartbeat() bool {
+	return l.probe == nil
+}
+
+// watch blocks until the given context is done or the liveness check of the
+// child fails; when the later happens, it returns a LivenessTimeoutError.
+func (l Liveness) watch(
+	ctx context.Context,
+	chRuntimeName string,
+	beatCh <-chan struct{},
+) *LivenessTimeoutError {
+	if l.isHeartbeat() {
+		return l.watchHeartbeat(ctx, chRuntimeName, beatCh)
+	}
+	return l.watchProbe(ctx, chRuntimeName)
+}
+
+// watchHeartbeat fails when the child does not call Heartbeat within the
+// liveness timeout
+func (l Liveness) watchHeartbeat(
+	ctx context.Context,
+	chRuntimeName string,
+	beatCh <-chan struct{},
+) *LivenessTimeoutError {
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-beatCh:
+			timer.Reset(l.timeout)
+		case <-timer.C:
+			return &LivenessTimeoutError{
+				nodeName: chRuntimeName,
+				timeout:  l.timeout,
+			}
+		}
+	}
+}
+
+// watchProbe runs the liveness probe every interval, it fails when the probe
+// returns an error or when it does not return within the liveness timeout
+func (l Liveness) watchProbe(
+	ctx context.Context,
+	chRuntimeName string,
+) *LivenessTimeoutError {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			ok, probeErr := l.runProbe(ctx)
+			// probe errors caused by the termination of the child are ignored
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !ok || probeErr != nil {
+				return &LivenessTimeoutError{
+					nodeName: chRuntimeName,
+					timeout:  l.timeout,
+					probeErr: probeErr,
+				}
+			}
+		}
+	}
+}
+
+// runProbe executes the liveness probe on a new goroutine. The first return
+// value is false when the probe did not return within the liveness timeout; in
+// that situation, the probe goroutine is left running until it returns.
+func (l Liveness) runProbe(ctx context.Context) (bool, error) {
+	probeCtx, cancelFn := context.WithTimeout(ctx, l.timeout)
+	defer cancelFn()
+
+	// buffered so that a late probe does not block forever
+	resultCh := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if panicVal := recover(); panicVal != nil {
+				resultCh <- fmt.Errorf("liveness probe panic: %v", panicVal)
+			}
+		}()
+		resultCh <- l.probe(probeCtx)
+	}()
+
+	select {
+	case probeErr := <-resultCh:
+		return true, probeErr
+	case <-probeCtx.Done():
+		return false, nil
+	}
+}
+
+// LivenessTimeoutError is the error reported by a child when it does not pass
+// the liveness check specified with WithLivenessProbe or WithHeartbeat.
+type LivenessTimeoutError struct {
+	nodeName    string
+	timeout     time.Duration
+	probeErr    error
+	shutdownErr *ShutdownTimeoutError
+}
+
+// Error returns an error message
+func (err *LivenessTimeoutError) Error() string {
+	if err.probeErr != nil {
+		return fmt.Sprintf("liveness probe failed: %v", err.probeErr)
+	}
+	return fmt.Sprintf("liveness timeout after %v", err.timeout)
+}
+
+// Unwrap returns the error reported by the liveness probe, if any
+func (err *LivenessTimeoutError) Unwrap() error {
+	return err.probeErr
+}
+
+// As finds a ShutdownTimeoutError when the child did not stop within its
+// shutdown timeout after it failed the liveness check, and it got abandoned
+func (err *LivenessTimeoutError) As(target interface{}) bool {
+	if shutdownErr, ok := target.(**ShutdownTimeoutError); ok && err.shutdownErr != nil {
+		*shutdownErr = err.shutdownErr
+		return true
+	}
+	return false
+}
+
+// KVs returns a data bag map that may be used in structured logging
+func (err *LivenessTimeoutError) KVs() map[string]interface{} {
+	kvs := make(map[string]interface{})
+	kvs["node.name"] = err.nodeName
+	kvs["node.liveness.timeout"] = err.timeout
+	if err.probeErr != nil {
+		kvs["node.liveness.error"] = err.probeErr.Error()
+	}
+	return kvs
+}
@@ -0,0 +1,32 @@
+package c
+
+import "context"
+
+// NodeContextFn is a function that gets called every time a node of the
+// supervision tree starts. It receives the context the node is going to run
+// with, the runtime name and the tag of the node; the returned context is used
+// instead.
+type NodeContextFn func(ctx context.Context, runtimeName string, nodeTag ChildTag) context.Context
+
+// nodeContextFnKey is an internal representation of the NodeContextFn in the
+// node context.
+var nodeContextFnKey capatazKey = "__capataz.node.context_fn__"
+
+// WithNodeContextFn allows to add a NodeContextFn to a context, the children
+// started with the returned context run with the context the function returns.
+func WithNodeContextFn(ctx context.Context, fn NodeContextFn) context.Context {
+	return context.WithValue(ctx, nodeContextFnKey, fn)
+}
+
+// applyNodeContextFn returns the context a child with the given runtime name
+// and tag runs with
+func applyNodeContextFn(
+	ctx context.Context,
+	runtimeName string,
+	nodeTag ChildTag,
+) context.Context {
+	if fn, _ := ctx.Value(nodeContextFnKey).(NodeContextFn); fn != nil {
+		return fn(ctx, runtimeName, nodeTag)
+	}
+	return ctx
+}
@@ -1,6 +1,9 @@
 package c
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // WithRestart specifies how the parent supervisor should restart this worker
 // after an error is encountered.
@@ -18,6 +21,34 @@ func WithCapturePanic(capture bool) Opt {
 	}
 }
 
+// WithPanicPolicy specifies what happens when this worker panics. This option
+// overrides the panic policy of the parent supervisor.
+//
+// Possible values may be:
+//
+// * RestartOnPanic -- The panic is reported as an error, and the worker is
+// restarted as with any other error
+//
+// * EscalateOnPanic -- The panic is reported as an error, and the parent
+// supervisor fails right away without restarting the worker
+//
+// * CrashOnPanic -- The panic is not captured, crashing the program
+func WithPanicPolicy(pp PanicPolicy) Opt {
+	return func(spec *ChildSpec) {
+		spec.CapturePanic = true
+		spec.PanicPolicy = pp
+	}
+}
+
+// WithLabels specifies labels that are attached to the events the supervision
+// system emits for the child. The labels are merged with the labels inherited
+// from the parent supervisor; on conflicting keys, the labels of the child win.
+func WithLabels(labels map[string]string) Opt {
+	return func(spec *ChildSpec) {
+		spec.Labels = MergeLabels(spec.Labels, labels)
+	}
+}
+
 // WithShutdown specifies how the shutdown of the worker is going to be handled.
 // Read `Indefinitely` and `Timeout` shutdown values documentation for details.
 func WithShutdown(s Shutdown) Opt {
@@ -26,13 +57,141 @@ func WithShutdown(s Shutdown) Opt {
 	}
 }
 
+// WithRestartBackoff specifies how much time the supervisor of this worker
+// should wait before restarting it.
+//
+// The first restart is delayed by the initial duration, and every consecutive
+// restart multiplies the previous delay by the given multiplier, up to the max
+// duration. The jitter (a value between 0 and 1) reduces each delay by a random
+// fraction of it, so that workers that failed at the same time do not get
+// restarted in lockstep. Once the worker stays up for longer than the max
+// duration, the delay is reset to the initial duration.
+//
+// This function panics if initial is not positive, max is lower than initial,
+// multiplier is lower than 1 or jitter is not between 0 and 1.
+func WithRestartBackoff(
+	initial, max time.Duration,
+	multiplier, jitter float64,
+) Opt {
+	if initial <= 0 {
+		panic("restart backoff initial duration must be positive")
+	}
+	if max < initial {
+		panic("restart backoff max duration must be greater or equal than initial")
+	}
+	if multiplier < 1 {
+		panic("restart backoff multiplier must be greater or equal than 1")
+	}
+	if jitter < 0 || jitter > 1 {
+		panic("restart backoff jitter must be between 0 and 1")
+	}
+	return func(spec *ChildSpec) {
+		spec.Backoff = RestartBackoff{
+			initial:    initial,
+			max:        max,
+			multiplier: multiplier,
+			jitter:     jitter,
+		}
+	}
+}
+
 // WithTolerance specifies to the supervisor monitor of this worker how many
-// errors it should be willing to tolerate before giving up restarting it and
-// fail.
+// errors it should be willing to tolerate before giving up restarting it.
 //
-// Deprecated: Use WithRestartTolerance instead.
+// Errors of this worker are accounted on this tolerance first, and then on the
+// restart tolerance of the supervisor. Check WithToleranceAction to specify
+// what happens when this tolerance is surpassed.
 func WithTolerance(maxErrCount uint32, errWindow time.Duration) Opt {
-	return func(spec *ChildSpec) {}
+	return func(spec *ChildSpec) {
+		spec.Tolerance = &Tolerance{
+			maxErrCount: maxErrCount,
+			errWindow:   errWindow,
+		}
+	}
+}
+
+// WithToleranceAction specifies what the supervisor monitor of this worker does
+// when the worker surpasses the tolerance given with WithTolerance.
+func WithToleranceAction(a ToleranceAction) Opt {
+	return func(spec *ChildSpec) {
+		spec.ToleranceAction = a
+	}
+}
+
+// WithLivenessProbe specifies a probe the parent supervisor of this worker runs
+// every interval while the worker is running. When the probe returns an error,
+// or when it does not return within the given timeout, the supervisor cancels
+// the worker and treats it as failed with a LivenessTimeoutError.
+//
+// This function panics if interval or timeout are not positive, or if the probe
+// is nil.
+func WithLivenessProbe(
+	interval, timeout time.Duration,
+	probe func(context.Context) error,
+) Opt {
+	if interval <= 0 {
+		panic("liveness probe interval must be positive")
+	}
+	if timeout <= 0 {
+		panic("liveness probe timeout must be positive")
+	}
+	if probe == nil {
+		panic("liveness probe cannot be nil")
+	}
+	return func(spec *ChildSpec) {
+		spec.Liveness = &Liveness{
+			interval: interval,
+			timeout:  timeout,
+			probe:    probe,
+		}
+	}
+}
+
+// WithHeartbeat specifies that this worker must call Heartbeat with its context
+// at least once every timeout. When the worker misses a heartbeat, its parent
+// supervisor cancels the worker and treats it as failed with a
+// LivenessTimeoutError.
+//
+// This function panics if timeout is not positive.
+func WithHeartbeat(timeout time.Duration) Opt {
+	if timeout <= 0 {
+		panic("heartbeat timeout must be positive")
+	}
+	return func(spec *ChildSpec) {
+		spec.Liveness = &Liveness{timeout: timeout}
+	}
+}
+
+// WithStartTimeout specifies how much time the parent supervisor waits for this
+// worker to call its NotifyStartFn. When the timeout is reached, the worker's
+// context is canceled and its start fails with a StartTimeoutError.
+//
+// This function panics if the given duration is not positive.
+func WithStartTimeout(d time.Duration) Opt {
+	if d <= 0 {
+		panic("start timeout must be positive")
+	}
+	return func(spec *ChildSpec) {
+		spec.StartTimeout = d
+	}
+}
+
+// WithReadiness specifies that this worker reports it is ready to do its work
+// by calling NotifyReady with its context, some time after it started.
+func WithReadiness() Opt {
+	return func(spec *ChildSpec) {
+		spec.Readiness = true
+	}
+}
+
+// WithCaptureAbandonedStack specifies that the stack of this worker goroutine
+// must be captured when the parent supervisor gives up on its termination (see
+// AbandonedProcesses). Capturing the stack requires a dump of all the running
+// goroutines, use this option for debugging purposes.
+func WithCaptureAbandonedStack() Opt {
+	return func(spec *ChildSpec) {
+		spec.CaptureAbandonedStack = true
+	}
 }
 
 // WithTag sets the given c.ChildTag on a c.ChildSpec
@@ -0,0 +1,42 @@
+package c
+
+import "fmt"
+
+// PanicError is the error reported when a panic is captured by the supervision
+// system. It holds the value given to panic and the stack trace of the
+// goroutine that panicked.
+type PanicError struct {
+	value interface{}
+	stack []byte
+}
+
+// NewPanicError creates a PanicError from a recovered panic value and the stack
+// trace of the goroutine that panicked (see runtime/debug.Stack).
+func NewPanicError(value interface{}, stack []byte) *PanicError {
+	return &PanicError{value: value, stack: stack}
+}
+
+// Error returns an error message, when the panic value is an error, its message
+// is returned
+func (err *PanicError) Error() string {
+	if valErr, ok := err.value.(error); ok {
+		return valErr.Error()
+	}
+	return fmt.Sprintf("panic error: %v", err.value)
+}
+
+// Unwrap returns the panic value when it is an error
+func (err *PanicError) Unwrap() error {
+	valErr, _ := err.value.(error)
+	return valErr
+}
+
+// GetValue returns the value that was given to panic
+func (err *PanicError) GetValue() interface{} {
+	return err.value
+}
+
+// GetStack returns the stack trace of the goroutine that panicked
+func (err *PanicError) GetStack() []byte {
+	return err.stack
+}
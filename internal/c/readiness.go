@@ -0,0 +1,71 @@
+package c
+
+import (
+	"context"
+	"sync"
+)
+
+// readinessKey is an internal representation of the readiness of a worker in
+// the worker context.
+var readinessKey capatazKey = "__capataz.node.readiness__"
+
+// readiness tracks if a child that was created with the WithReadiness option
+// reported it is ready
+type readiness struct {
+	once    sync.Once
+	readyCh chan struct{}
+}
+
+// newReadiness creates a new readiness
+func newReadiness() *readiness {
+	return &readiness{readyCh: make(chan struct{})}
+}
+
+// notify marks the child as ready, it is safe to call this function multiple
+// times
+func (r *readiness) notify() {
+	r.once.Do(func() {
+		close(r.readyCh)
+	})
+}
+
+// setReadiness allows to add the readiness of a child to a context. Children
+// without the WithReadiness option get a nil readiness, so that they do not
+// report the readiness of a parent worker.
+func setReadiness(ctx context.Context, r *readiness) context.Context {
+	return context.WithValue(ctx, readinessKey, r)
+}
+
+// NotifyReady reports to the parent supervisor that the worker running with the
+// given context is ready to do its work. This function is a no-op when the
+// worker was not created with the WithReadiness option, or when it already
+// reported it is ready.
+func NotifyReady(ctx context.Context) {
+	if r, _ := ctx.Value(readinessKey).(*readiness); r != nil {
+		r.notify()
+	}
+}
+
+// WaitReady blocks until this child reports it is ready (see NotifyReady), it
+// returns false if the child finished before reporting it is ready, or if the
+// given cancelCh is closed first. Children that were not created with the
+// WithReadiness option are ready once they start.
+func (c Child) WaitReady(cancelCh <-chan struct{}) bool {
+	if c.readyCh == nil {
+		return true
+	}
+	select {
+	case <-c.readyCh:
+		return true
+	case <-c.doneCh:
+		// the child may have reported it was ready before finishing
+		select {
+		case <-c.readyCh:
+			return true
+		default:
+			return false
+		}
+	case <-cancelCh:
+		return false
+	}
+}
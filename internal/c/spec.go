@@ -2,6 +2,9 @@ package c
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"math/rand"
 	"time"
 )
 
@@ -76,6 +79,26 @@ const (
 type Shutdown struct {
 	tag      ShutdownTag
 	duration time.Duration
+	// grace is the duration the parent supervisor waits for the child goroutine
+	// to stop after it requested it to stop, and before its context is canceled
+	grace time.Duration
+}
+
+// String returns a string representation of the Shutdown value
+func (s Shutdown) String() string {
+	if s.grace > 0 {
+		hard := s
+		hard.grace = 0
+		return fmt.Sprintf("Graceful(%v, %v)", s.grace, hard)
+	}
+	switch s.tag {
+	case indefinitelyT:
+		return "Indefinitely"
+	case timeoutT:
+		return fmt.Sprintf("Timeout(%v)", s.duration)
+	default:
+		return "<Unknown>"
+	}
 }
 
 // Indefinitely specifies the parent supervisor must wait indefinitely for child
@@ -100,6 +123,177 @@ func Timeout(d time.Duration) Shutdown {
 	}
 }
 
+// Graceful specifies a two-phase shutdown: the parent supervisor first
+// requests the child goroutine to stop (see ShutdownRequested) and waits for it
+// up to the given grace period; if the child goroutine is still running after
+// the grace period, its context is canceled and the parent supervisor waits for
+// it as specified by the given Shutdown value.
+//
+// This shutdown allows workers to finish in-flight work (e.g. requests) before
+// the resources bound to their context are released.
+func Graceful(grace time.Duration, then Shutdown) Shutdown {
+	if grace <= 0 {
+		panic("Graceful requires a positive grace period")
+	}
+	then.grace = grace
+	return then
+}
+
+// Deadline returns the time at which a shutdown that starts at the given time
+// times out. It returns a zero time.Time when the Shutdown value is
+// Indefinitely.
+func (s Shutdown) Deadline(from time.Time) time.Time {
+	if s.tag != timeoutT {
+		return time.Time{}
+	}
+	return from.Add(s.grace + s.duration)
+}
+
+// within returns a Shutdown value that does not wait past the given deadline;
+// a zero deadline returns the Shutdown value unchanged. When the deadline is
+// done, the returned Shutdown has a zero timeout, which does not wait for
+// children that are still running.
+func (s Shutdown) within(deadline time.Time) Shutdown {
+	if deadline.IsZero() {
+		return s
+	}
+	remaining := time.Until(deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+	if s.tag == timeoutT && s.duration < remaining {
+		return s
+	}
+	return Timeout(remaining)
+}
+
+// RestartBackoff specifies the delay the parent supervisor waits before
+// restarting a child goroutine. The delay grows exponentially on every
+// consecutive restart (up to a maximum) and it gets reset once the child stays
+// up for a period longer than the maximum delay.
+//
+// The zero value of RestartBackoff restarts the child goroutine immediately.
+type RestartBackoff struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+	jitter     float64
+}
+
+// isEnabled indicates if this RestartBackoff delays restarts at all
+func (rb RestartBackoff) isEnabled() bool {
+	return rb.initial > 0
+}
+
+// getDelay returns the delay for the given number of consecutive restarts. The
+// returned value is reduced by a random factor that goes up to the specified
+// jitter.
+func (rb RestartBackoff) getDelay(attempt uint32) time.Duration {
+	if !rb.isEnabled() {
+		return 0
+	}
+	delay := float64(rb.initial) * math.Pow(rb.multiplier, float64(attempt))
+	if delay > float64(rb.max) {
+		delay = float64(rb.max)
+	}
+	if rb.jitter > 0 {
+		delay -= delay * rb.jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// ToleranceAction specifies what the parent supervisor does with a child that
+// surpassed its own restart tolerance
+type ToleranceAction uint32
+
+const (
+	// GiveUp specifies that the parent supervisor must stop restarting the
+	// child, the supervisor and the child's siblings keep running.
+	GiveUp ToleranceAction = iota
+	// Escalate specifies that the parent supervisor must fail, delegating the
+	// error handling to its own parent supervisor.
+	Escalate
+)
+
+func (ta ToleranceAction) String() string {
+	switch ta {
+	case GiveUp:
+		return "GiveUp"
+	case Escalate:
+		return "Escalate"
+	default:
+		return "<Unknown>"
+	}
+}
+
+// PanicPolicy specifies what happens when a child goroutine panics
+type PanicPolicy uint32
+
+const (
+	// the zero value uses the panic policy of the parent supervisor
+	inheritPanicPolicy PanicPolicy = iota
+	// RestartOnPanic specifies the panic is captured and reported as an error of
+	// the child, the parent supervisor restarts the child as with any other
+	// error
+	RestartOnPanic
+	// EscalateOnPanic specifies the panic is captured and the parent supervisor
+	// fails right away without restarting the child, regardless of restart
+	// tolerances; the failure is handled by its own parent supervisor.
+	EscalateOnPanic
+	// CrashOnPanic specifies the panic is not captured, crashing the program
+	CrashOnPanic
+)
+
+func (pp PanicPolicy) String() string {
+	switch pp {
+	case inheritPanicPolicy:
+		return "Inherit"
+	case RestartOnPanic:
+		return "RestartOnPanic"
+	case EscalateOnPanic:
+		return "EscalateOnPanic"
+	case CrashOnPanic:
+		return "CrashOnPanic"
+	default:
+		return "<Unknown>"
+	}
+}
+
+// Tolerance specifies how many errors a child may report over a window of
+// time before its parent supervisor stops restarting it.
+type Tolerance struct {
+	maxErrCount uint32
+	errWindow   time.Duration
+}
+
+// GetMaxErrCount returns the number of errors that are tolerated in the
+// tolerance window
+func (t Tolerance) GetMaxErrCount() uint32 {
+	return t.maxErrCount
+}
+
+// GetErrWindow returns the duration of the tolerance window
+func (t Tolerance) GetErrWindow() time.Duration {
+	return t.errWindow
+}
+
+// StartTimeoutError is the error reported by a child that did not notify it
+// started within the duration specified with WithStartTimeout.
+type StartTimeoutError struct {
+	nodeName string
+	timeout  time.Duration
+}
+
+// Error returns an error message
+func (err *StartTimeoutError) Error() string {
+	return fmt.Sprintf("start timeout after %v", err.timeout)
+}
+
+// GetNodeName returns the runtime name of the child that did not start on time
+func (err *StartTimeoutError) GetNodeName() string {
+	return err.nodeName
+}
+
 // startError is the error reported back to a Supervisor when the start of a
 // Child fails
 type startError = error
@@ -133,6 +327,39 @@ type ChildSpec struct {
 	Shutdown     Shutdown
 	Restart      Restart
 	CapturePanic bool
+	Backoff      RestartBackoff
+
+	// PanicPolicy specifies what happens when the child panics, the zero value
+	// uses the panic policy of the parent supervisor. When CapturePanic is
+	// false, panics are never captured.
+	PanicPolicy PanicPolicy
+
+	Tolerance       *Tolerance
+	ToleranceAction ToleranceAction
+
+	// Liveness is nil when the parent supervisor does not check the progress of
+	// this child while it runs
+	Liveness *Liveness
+
+	// StartTimeout is the time the parent supervisor waits for the child to
+	// notify it started, it is zero when the supervisor waits indefinitely
+	StartTimeout time.Duration
+
+	// Readiness is true when this child reports it is ready some time after it
+	// started
+	Readiness bool
+
+	// CaptureAbandonedStack is true when the stack of this child is captured if
+	// the parent supervisor gives up on its termination
+	CaptureAbandonedStack bool
+
+	// Labels are attached to the events emitted by the supervision system for
+	// this child; they include the labels inherited from the parent supervisor
+	Labels map[string]string
+
+	// Ctrl is an opaque reference that allows the supervision system to send
+	// control messages to a child that runs a supervision sub-tree
+	Ctrl interface{}
 
 	Start func(context.Context, NotifyStartFn) error
 }
@@ -152,7 +379,76 @@ func (chSpec ChildSpec) GetRestart() Restart {
 	return chSpec.Restart
 }
 
+// GetRestartBackoff returns the RestartBackoff setting for this ChildSpec
+func (chSpec ChildSpec) GetRestartBackoff() RestartBackoff {
+	return chSpec.Backoff
+}
+
+// GetTolerance returns the restart Tolerance of this ChildSpec, if this child
+// does not have a restart tolerance of its own, the returned value is nil
+func (chSpec ChildSpec) GetTolerance() *Tolerance {
+	return chSpec.Tolerance
+}
+
+// GetToleranceAction returns what the parent supervisor does when this child
+// surpasses its own restart tolerance
+func (chSpec ChildSpec) GetToleranceAction() ToleranceAction {
+	return chSpec.ToleranceAction
+}
+
+// GetLiveness returns the Liveness check of this ChildSpec, if this child is
+// not checked, the returned value is nil
+func (chSpec ChildSpec) GetLiveness() *Liveness {
+	return chSpec.Liveness
+}
+
+// GetStartTimeout returns the time the parent supervisor waits for this child
+// to notify it started, a zero value means the supervisor waits indefinitely
+func (chSpec ChildSpec) GetStartTimeout() time.Duration {
+	return chSpec.StartTimeout
+}
+
+// ReportsReadiness indicates if this child reports it is ready some time after
+// it started
+func (chSpec ChildSpec) ReportsReadiness() bool {
+	return chSpec.Readiness
+}
+
+// GetLabels returns the labels attached to the events of this child
+func (chSpec ChildSpec) GetLabels() map[string]string {
+	return chSpec.Labels
+}
+
+// GetCtrl returns the control reference of a child that runs a supervision
+// sub-tree, for worker children it returns nil
+func (chSpec ChildSpec) GetCtrl() interface{} {
+	return chSpec.Ctrl
+}
+
 // DoesCapturePanic indicates if this child handles panics
 func (chSpec ChildSpec) DoesCapturePanic() bool {
-	return chSpec.CapturePanic
+	return chSpec.GetPanicPolicy() != CrashOnPanic
+}
+
+// GetPanicPolicy returns what happens when this child panics
+func (chSpec ChildSpec) GetPanicPolicy() PanicPolicy {
+	if !chSpec.CapturePanic {
+		return CrashOnPanic
+	}
+	if chSpec.PanicPolicy == inheritPanicPolicy {
+		return RestartOnPanic
+	}
+	return chSpec.PanicPolicy
+}
+
+// InheritsPanicPolicy indicates if the panic policy of this child was not
+// specified, in which case, the panic policy of the parent supervisor is used
+func (chSpec ChildSpec) InheritsPanicPolicy() bool {
+	return chSpec.CapturePanic && chSpec.PanicPolicy == inheritPanicPolicy
+}
+
+// DoesCaptureAbandonedStack indicates if the stack of this child is captured
+// when the parent supervisor gives up on its termination
+func (chSpec ChildSpec) DoesCaptureAbandonedStack() bool {
+	return chSpec.CaptureAbandonedStack
 }
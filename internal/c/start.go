@@ -2,9 +2,11 @@ package c
 
 import (
 	"context"
-	"errors"
-	"fmt"
+	"runtime/debug"
+	"runtime/pprof"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -31,11 +33,24 @@ func setNodeName(ctx context.Context, name string) context.Context {
 }
 
 // waitTimeout is the internal function used by Child to wait for the execution
-// of it's thread to stop.
+// of it's thread to stop. When the given Shutdown times out, the child gets
+// abandoned with the given abandon function.
 func waitTimeout(
+	chRuntimeName string,
 	terminateCh <-chan ChildNotification,
+	doneCh <-chan struct{},
+	abandonCh <-chan struct{},
+	abandon func(),
 ) func(Shutdown) (bool, error) {
 	return func(shutdown Shutdown) (bool, error) {
+		// the supervisor gave up on this child before, there is nothing to wait
+		// for
+		select {
+		case <-abandonCh:
+			return false, nil
+		default:
+		}
+
 		switch shutdown.tag {
 		case indefinitelyT:
 			// We wait forever for the result
@@ -46,6 +61,31 @@ func waitTimeout(
 			// A child may have terminated with an error
 			return true, childNotification.Unwrap()
 		case timeoutT:
+			// there is no time left to wait (e.g. the deadline of the parent
+			// supervisor is done), we only collect the result of a child that
+			// already finished; a finished child sends its notification right
+			// away
+			if shutdown.duration <= 0 {
+				select {
+				case childNotification, ok := <-terminateCh:
+					if !ok {
+						return false, nil
+					}
+					return true, childNotification.Unwrap()
+				case <-doneCh:
+					childNotification, ok := <-terminateCh
+					if !ok {
+						return false, nil
+					}
+					return true, childNotification.Unwrap()
+				default:
+					abandon()
+					return true, &ShutdownTimeoutError{
+						nodeName: chRuntimeName,
+						timeout:  shutdown.duration,
+					}
+				}
+			}
 			// we wait until some duration
 			select {
 			case childNotification, ok := <-terminateCh:
@@ -55,7 +95,11 @@ func waitTimeout(
 				// A child may have terminated with an error
 				return true, childNotification.Unwrap()
 			case <-time.After(shutdown.duration):
-				return true, errors.New("child shutdown timeout")
+				abandon()
+				return true, &ShutdownTimeoutError{
+					nodeName: chRuntimeName,
+					timeout:  shutdown.duration,
+				}
 			}
 		default:
 			// This should never happen if we use the already defined Shutdown types
@@ -72,7 +116,16 @@ func sendNotificationToSup(
 	chRuntimeName string,
 	supNotifyChan chan<- ChildNotification,
 	terminateCh chan<- ChildNotification,
+	abandonCh <-chan struct{},
 ) {
+	// the supervisor gave up on this child (e.g. start timeout), it does not
+	// know about this child anymore
+	select {
+	case <-abandonCh:
+		return
+	default:
+	}
+
 	chNotification := ChildNotification{
 		name:        chSpec.GetName(),
 		tag:         chSpec.GetTag(),
@@ -93,11 +146,16 @@ func sendNotificationToSup(
 	// function, which calls the `child.Terminate` method for each of the supervised
 	// internally, this function reads the `terminateCh`.
 	//
+	// 3) If the supervisor gave up on this child while we were waiting, the
+	// notification is dropped.
+	//
 	select {
 	// (1)
 	case supNotifyChan <- chNotification:
 	// (2)
 	case terminateCh <- chNotification:
+	// (3)
+	case <-abandonCh:
 	}
 }
 
@@ -123,22 +181,104 @@ func (chSpec ChildSpec) DoStart(
 	// we remove the cancel from the context received on the start call so that we
 	// don't end up canceling the children at a non-appropiate time
 	ctx := WithoutCancel(startCtx)
+	ctx = applyNodeContextFn(ctx, chRuntimeName, chSpec.GetTag())
+
+	liveness := chSpec.GetLiveness()
+
+	// beatCh is used by the child to report heartbeats, it is nil when the child
+	// does not have a heartbeat liveness
+	var beatCh chan struct{}
+	if liveness != nil && liveness.isHeartbeat() {
+		beatCh = make(chan struct{}, 1)
+	}
+
+	// chReadiness is used by the child to report it is ready, it is nil when the
+	// child does not report its readiness
+	var chReadiness *readiness
+	var readyCh chan struct{}
+	if chSpec.ReportsReadiness() {
+		chReadiness = newReadiness()
+		readyCh = chReadiness.readyCh
+	}
 
 	// we allow a node to know it's name so as to allow subtrees to report
 	// events with it's full name
-	childCtx, cancelFn := context.WithCancel(setNodeName(ctx, chRuntimeName))
+	childCtx, cancelFn := context.WithCancel(
+		setReadiness(
+			setHeartbeatCh(setNodeName(ctx, chRuntimeName), beatCh),
+			chReadiness,
+		),
+	)
 
-	startCh := make(chan startError)
+	// stopCtx is done when the supervisor requests the child to stop, or when
+	// the childCtx is done; children without a Graceful shutdown observe the
+	// childCtx only
+	stopCtx, stopFn := context.WithCancel(childCtx)
+	var stopCh <-chan struct{}
+	if chSpec.Shutdown.grace > 0 {
+		stopCh = stopCtx.Done()
+	}
+	workerCtx := setStopCh(childCtx, stopCh)
+
+	// buffered so that a child that notifies its start after the start timeout
+	// does not block forever
+	startCh := make(chan startError, 1)
 	terminateCh := make(chan ChildNotification)
 
+	// doneCh is closed when the client logic returns, before the supervisor is
+	// notified
+	doneCh := make(chan struct{})
+	var doneOnce sync.Once
+	markDone := func() { doneOnce.Do(func() { close(doneCh) }) }
+
+	// livenessErr is set when the child is canceled because it did not pass its
+	// liveness check
+	var livenessErr atomic.Value
+
+	// abandonCh is closed when the supervisor gives up on the child, either on
+	// start or on termination
+	abandonCh := make(chan struct{})
+	var abandonOnce sync.Once
+	closeAbandonCh := func() { abandonOnce.Do(func() { close(abandonCh) }) }
+
+	// runLabel identifies the goroutines of the child on the goroutine profile,
+	// it is set when the stack of the child must be captured if the supervisor
+	// gives up on its termination
+	var runLabel string
+	if chSpec.DoesCaptureAbandonedStack() {
+		workerCtx, runLabel = setAbandonedRunLabel(workerCtx)
+	}
+
+	// abandon is called when the child does not stop within its shutdown
+	// timeout, the child is tracked in the abandoned registry until it finishes
+	abandon := func() {
+		closeAbandonCh()
+		registerAbandoned(chRuntimeName, runLabel, doneCh)
+	}
+
+	// the child goroutine and the liveness watcher may report the termination
+	// of the child, only the first report is sent to the supervisor
+	var notifyOnce sync.Once
+	notifySup := func(err error) {
+		notifyOnce.Do(func() {
+			sendNotificationToSup(
+				err,
+				chSpec,
+				chRuntimeName,
+				supNotifyChan,
+				terminateCh,
+				abandonCh,
+			)
+			// we tell the spawner this child thread has stopped. We want to
+			// close this channel after the worker is done so that on the
+			// scenario the termination logic is called again, the call
+			// returns immediatelly and without errors
+			close(terminateCh)
+		})
+	}
+
 	// Child Goroutine is bootstraped
 	go func() {
-		// we tell the spawner this child thread has stopped. We want to
-		// close this channel after the worker is done so that on the
-		// scenario the termination logic is called again, the call
-		// returns immediatelly and without errors
-		defer close(terminateCh)
-
 		// we cancel the childCtx on regular termination
 		defer cancelFn()
 
@@ -151,24 +291,21 @@ func (chSpec ChildSpec) DoStart(
 					return
 				}
 
-				panicErr, ok := panicVal.(error)
-				if !ok {
-					panicErr = fmt.Errorf("panic error: %v", panicVal)
-				}
-				sendNotificationToSup(
-					panicErr,
-					chSpec,
-					chRuntimeName,
-					supNotifyChan,
-					terminateCh,
-				)
+				notifySup(NewPanicError(panicVal, debug.Stack()))
 			}
 		}()
 
+		// on panics, the client logic is done before the panic is notified
+		defer markDone()
+
+		if runLabel != "" {
+			pprof.SetGoroutineLabels(workerCtx)
+		}
+
 		// client logic starts here, despite the call here being a "start", we will
 		// block and wait here until an error (or lack of) is reported from the
 		// client code
-		err := chSpec.Start(childCtx, func(err error) {
+		err := chSpec.Start(workerCtx, func(err error) {
 			// we tell the spawner this child thread has started running
 			if err != nil {
 				startCh <- err
@@ -176,26 +313,82 @@ func (chSpec ChildSpec) DoStart(
 			close(startCh)
 		})
 
-		sendNotificationToSup(
-			err,
-			chSpec,
-			chRuntimeName,
-			supNotifyChan,
-			terminateCh,
-		)
+		markDone()
+
+		// a child that got canceled by its liveness check is reported as failed
+		if lErr, ok := livenessErr.Load().(*LivenessTimeoutError); ok {
+			err = lErr
+		}
+
+		notifySup(err)
 	}()
 
 	// Wait until child thread notifies it has started or failed with an error
-	err := <-startCh
+	var err error
+	if startTimeout := chSpec.GetStartTimeout(); startTimeout > 0 {
+		select {
+		case err = <-startCh:
+		case <-time.After(startTimeout):
+			closeAbandonCh()
+			cancelFn()
+			stopFn()
+			return Child{}, &StartTimeoutError{
+				nodeName: chRuntimeName,
+				timeout:  startTimeout,
+			}
+		}
+	} else {
+		err = <-startCh
+	}
 	if err != nil {
+		// the supervisor does not keep track of children that failed to start
+		closeAbandonCh()
+		stopFn()
 		return Child{}, err
 	}
 
+	if liveness != nil {
+		go func() {
+			lErr := liveness.watch(childCtx, chRuntimeName, beatCh)
+			if lErr == nil {
+				return
+			}
+			livenessErr.Store(lErr)
+			cancelFn()
+
+			// a hung child may not respect the cancelation of its context, when it
+			// does not return within its shutdown timeout, we report the failure
+			// on its behalf; the supervisor restarts the child while the hung
+			// goroutine keeps running, so it is tracked as abandoned, and the
+			// reported error is also a ShutdownTimeoutError. Note the abandonCh
+			// is not closed, the supervisor still needs to receive the
+			// notification.
+			if chSpec.Shutdown.tag != timeoutT {
+				return
+			}
+			select {
+			case <-doneCh:
+			case <-time.After(chSpec.Shutdown.duration):
+				registerAbandoned(chRuntimeName, runLabel, doneCh)
+				abandonedErr := *lErr
+				abandonedErr.shutdownErr = &ShutdownTimeoutError{
+					nodeName: chRuntimeName,
+					timeout:  chSpec.Shutdown.duration,
+				}
+				notifySup(&abandonedErr)
+			}
+		}()
+	}
+
 	return Child{
 		runtimeName: chRuntimeName,
 		createdAt:   time.Now(),
 		spec:        chSpec,
 		cancel:      cancelFn,
-		wait:        waitTimeout(terminateCh),
+		stop:        stopFn,
+		wait:        waitTimeout(chRuntimeName, terminateCh, doneCh, abandonCh, abandon),
+		terminateCh: terminateCh,
+		readyCh:     readyCh,
+		doneCh:      doneCh,
 	}, nil
 }
@@ -6,12 +6,24 @@ import (
 
 // Child is the runtime representation of a Spec
 type Child struct {
-	runtimeName  string
-	spec         ChildSpec
-	restartCount uint32
-	createdAt    time.Time
-	cancel       func()
-	wait         func(Shutdown) (bool, error)
+	runtimeName    string
+	spec           ChildSpec
+	restartCount   uint32
+	backoffAttempt uint32
+	// nextAttempt is the backoff attempt of the next runtime of this child, it
+	// is recorded when the supervisor handles the termination of this child
+	nextAttempt uint32
+	createdAt   time.Time
+	cancel      func()
+	stop        func()
+	wait        func(Shutdown) (bool, error)
+	terminateCh <-chan ChildNotification
+	readyCh     <-chan struct{}
+	doneCh      <-chan struct{}
+
+	toleranceErrCount  uint32
+	toleranceBeginTime time.Time
+	toleranceSourceErr error
 }
 
 // GetRuntimeName returns the name of this child (once started). It will have a
@@ -40,6 +52,86 @@ func (c Child) GetTag() ChildTag {
 	return c.spec.GetTag()
 }
 
+// GetCreatedAt returns the timestamp of when this child was started
+func (c Child) GetCreatedAt() time.Time {
+	return c.createdAt
+}
+
+// GetRestartCount returns the number of times this child has been restarted by
+// its parent supervisor, either because it terminated or because the restart
+// strategy of the supervisor restarted it along with a sibling
+func (c Child) GetRestartCount() uint32 {
+	return c.restartCount
+}
+
+// nextBackoffAttempt returns the number of consecutive restarts the next
+// runtime of this child is going to have. The count gets reset when the child
+// stayed up for longer than the max delay of its RestartBackoff.
+func (c Child) nextBackoffAttempt() uint32 {
+	if c.nextAttempt > 0 {
+		return c.nextAttempt
+	}
+	backoff := c.spec.GetRestartBackoff()
+	if c.backoffAttempt == 0 || time.Since(c.createdAt) >= backoff.max {
+		return 1
+	}
+	return c.backoffAttempt + 1
+}
+
+// GetRestartDelay returns the duration the parent supervisor must wait before
+// restarting this child, as specified by the RestartBackoff of its ChildSpec.
+func (c Child) GetRestartDelay() time.Duration {
+	return c.spec.GetRestartBackoff().getDelay(c.nextBackoffAttempt() - 1)
+}
+
+// WithTerminationHandled returns a copy of this child that records the backoff
+// attempt of its next runtime. The parent supervisor must call this function
+// when it handles the termination of this child, so that the backoff only gets
+// reset by the time this child was up, and not by the time its restart was
+// delayed.
+func (c Child) WithTerminationHandled() Child {
+	c.nextAttempt = c.nextBackoffAttempt()
+	return c
+}
+
+// GetToleranceState returns the failures accounted on the restart tolerance of
+// this child: the error count, the start time of the current tolerance window
+// and the first error reported on that window.
+func (c Child) GetToleranceState() (uint32, time.Time, error) {
+	return c.toleranceErrCount, c.toleranceBeginTime, c.toleranceSourceErr
+}
+
+// WithToleranceState returns a copy of this child with the given restart
+// tolerance state.
+func (c Child) WithToleranceState(
+	errCount uint32,
+	beginTime time.Time,
+	sourceErr error,
+) Child {
+	c.toleranceErrCount = errCount
+	c.toleranceBeginTime = beginTime
+	c.toleranceSourceErr = sourceErr
+	return c
+}
+
+// WithRestartStateOf returns a copy of this child that carries over the
+// restart bookkeeping of the given child; the given child must be the previous
+// runtime of the same ChildSpec.
+func (c Child) WithRestartStateOf(prev Child) Child {
+	c.restartCount = prev.restartCount + 1
+	// only children which termination was handled move forward on their
+	// backoff, siblings restarted by the restart strategy keep their attempt
+	if prev.nextAttempt > 0 {
+		c.backoffAttempt = prev.nextAttempt
+	} else {
+		c.backoffAttempt = prev.backoffAttempt
+	}
+	c.toleranceErrCount = prev.toleranceErrCount
+	c.toleranceBeginTime = prev.toleranceBeginTime
+	c.toleranceSourceErr = prev.toleranceSourceErr
+	return c
+}
+
 // ChildNotification reports when a child has terminated; if it terminated with
 // an error, it is set in the err field, otherwise, err will be nil.
 type ChildNotification struct {
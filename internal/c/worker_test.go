@@ -2,8 +2,10 @@ package c_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -54,3 +56,59 @@ func TestWorkerDoubleTermination(t *testing.T) {
 
 	})
 }
+
+func TestWorkerTerminateWithinDoneDeadline(t *testing.T) {
+	// a deadline that is already done
+	deadline := time.Now().Add(-1 * time.Second)
+
+	t.Run("on hung worker", func(t *testing.T) {
+		releaseCh := make(chan struct{})
+		defer close(releaseCh)
+
+		// internal way to transform a Node to a WorkerSpec
+		wspec := s.NewWorker(
+			"worker",
+			func(ctx context.Context) error {
+				// the worker does not respect the context cancelation
+				<-releaseCh
+				return nil
+			},
+			c.WithShutdown(c.Timeout(1*time.Second)),
+		)(s.SupervisorSpec{})
+
+		supNotifyChan := make(chan c.ChildNotification)
+		ch, err := wspec.DoStart(context.Background(), "test", supNotifyChan)
+		assert.NoError(t, err)
+
+		// the worker is abandoned right away, without waiting for its shutdown
+		// timeout
+		startTime := time.Now()
+		isFirstTime, err := ch.TerminateWithin(deadline)
+		assert.True(t, isFirstTime)
+		var timeoutErr *c.ShutdownTimeoutError
+		assert.True(t, errors.As(err, &timeoutErr))
+		assert.True(t, time.Since(startTime) < 500*time.Millisecond)
+	})
+
+	t.Run("on finished worker", func(t *testing.T) {
+		finishErr := errors.New("finished")
+
+		wspec := s.NewWorker(
+			"worker",
+			func(ctx context.Context) error {
+				return finishErr
+			},
+			c.WithShutdown(c.Timeout(1*time.Second)),
+		)(s.SupervisorSpec{})
+
+		supNotifyChan := make(chan c.ChildNotification)
+		ch, err := wspec.DoStart(context.Background(), "test", supNotifyChan)
+		assert.NoError(t, err)
+		<-ch.Done()
+
+		// the worker finished on its own, it is not abandoned
+		isFirstTime, err := ch.TerminateWithin(deadline)
+		assert.True(t, isFirstTime)
+		assert.ErrorIs(t, err, finishErr)
+	})
+}
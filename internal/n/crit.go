@@ -103,6 +103,15 @@ func EHasNameSuffix(rawSuffix string) EventCriteria {
 	}
 }
 
+// EHasLabel returns true if the node that emitted the event has a label with
+// the given key and value (see WithLabels and WithSupervisorLabels)
+func EHasLabel(key, value string) EventCriteria {
+	return func(ev s.Event) bool {
+		labelValue, ok := ev.GetLabels()[key]
+		return ok && labelValue == value
+	}
+}
+
 // ApplyEventCriteria forwards Event records that match positively the given
 // criteria to the given EventNotifier
 func ApplyEventCriteria(crit EventCriteria, notifier s.EventNotifier) s.EventNotifier {
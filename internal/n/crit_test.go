@@ -243,3 +243,50 @@ func TestEHasNameSuffix(t *testing.T) {
 
 	assert.Equal(t, 2, counter)
 }
+
+func TestEHasLabel(t *testing.T) {
+	counter := 0
+	evNotifier0 := func(s.Event) {
+		counter++
+	}
+
+	evNotifier := n.ApplyEventCriteria(n.EHasLabel("team", "billing"), evNotifier0)
+
+	b0 := s.NewSupervisorSpec(
+		"branch0",
+		s.WithNodes(WaitDoneWorker("child0")),
+		s.WithSupervisorLabels(map[string]string{"team": "billing"}),
+	)
+	b1 := s.NewSupervisorSpec("branch1", s.WithNodes(WaitDoneWorker("child1")))
+
+	events, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		s.WithNodes(
+			s.Subtree(b0),
+			s.Subtree(b1),
+		),
+		[]s.Opt{s.WithSupervisorLabels(map[string]string{"team": "platform"})},
+		[]s.EventNotifier{evNotifier},
+		func(EventManager) {},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/branch0/child0"), /* 1 */
+			SupervisorStarted("root/branch0"),    /* 2 */
+			WorkerStarted("root/branch1/child1"),
+			SupervisorStarted("root/branch1"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/branch1/child1"),
+			SupervisorTerminated("root/branch1"),
+			WorkerTerminated("root/branch0/child0"), /* 3 */
+			SupervisorTerminated("root/branch0"),    /* 4 */
+			SupervisorTerminated("root"),
+		},
+	)
+
+	assert.Equal(t, 4, counter)
+}
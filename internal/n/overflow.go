@@ -0,0 +1,109 @@
+package n
+
+import (
+	"sync"
+
+	"github.com/capatazlib/go-capataz/internal/s"
+)
+
+// OverflowPolicy specifies what a ReliableNotifier does with an event when the
+// buffer of the entrypoint or the queue of a notifier is full
+type OverflowPolicy uint32
+
+const (
+	// OverflowBlock waits until there is space on the buffer. Notifiers that
+	// are not ready to receive an event within the notifier timeout (see
+	// WithNotifierTimeout) get the event skipped. This is the default policy.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest event on the buffer to make space
+	// for the new event
+	OverflowDropOldest
+	// OverflowDropNewest discards the new event
+	OverflowDropNewest
+)
+
+// String returns a string representation of the current OverflowPolicy
+func (op OverflowPolicy) String() string {
+	switch op {
+	case OverflowBlock:
+		return "OverflowBlock"
+	case OverflowDropOldest:
+		return "OverflowDropOldest"
+	case OverflowDropNewest:
+		return "OverflowDropNewest"
+	default:
+		return "<Unknown>"
+	}
+}
+
+// sendEvent sends the given event to the given channel without blocking,
+// following the given drop policy when the channel buffer is full. It returns
+// the number of events that got dropped.
+func sendEvent(policy OverflowPolicy, ch chan s.Event, ev s.Event) uint64 {
+	var dropped uint64
+	for {
+		select {
+		case ch <- ev:
+			return dropped
+		default:
+		}
+
+		if policy != OverflowDropOldest {
+			return dropped + 1
+		}
+
+		select {
+		case <-ch:
+			dropped++
+		default:
+			// there is nothing to discard on the buffer (e.g. a channel without a
+			// buffer), we discard the new event instead
+			return dropped + 1
+		}
+	}
+}
+
+// DropCounter keeps track of the number of events a ReliableNotifier did not
+// deliver to each of its notifiers, either because of an OverflowPolicy that
+// drops events, or because the notifier was not ready to receive an event
+// within the notifier timeout. Events discarded on the entrypoint of the
+// ReliableNotifier are accounted on every notifier.
+//
+// The zero value is ready to use.
+type DropCounter struct {
+	mux    sync.Mutex
+	counts map[string]uint64
+}
+
+// add increments the drop count of the notifier with the given name
+func (dc *DropCounter) add(name string, count uint64) {
+	if dc == nil || count == 0 {
+		return
+	}
+	dc.mux.Lock()
+	defer dc.mux.Unlock()
+	if dc.counts == nil {
+		dc.counts = make(map[string]uint64)
+	}
+	dc.counts[name] += count
+}
+
+// Get returns the number of events that were not delivered to the notifier
+// with the given name
+func (dc *DropCounter) Get(name string) uint64 {
+	dc.mux.Lock()
+	defer dc.mux.Unlock()
+	return dc.counts[name]
+}
+
+// Snapshot returns the number of events that were not delivered to each
+// notifier, notifiers that did not miss events are not included
+func (dc *DropCounter) Snapshot() map[string]uint64 {
+	dc.mux.Lock()
+	defer dc.mux.Unlock()
+	result := make(map[string]uint64, len(dc.counts))
+	for name, count := range dc.counts {
+		result[name] = count
+	}
+	return result
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/capatazlib/go-capataz/internal/s"
 )
 
+// defaultNotifierQueueSize is the number of events that can wait to be
+// delivered to a single notifier
+const defaultNotifierQueueSize = 100
+
 // rootName is the name of the ReliableNotifier supervisor root tree node
 var rootName = "reliable-notifier"
 
@@ -16,7 +21,11 @@ var rootName = "reliable-notifier"
 // instance
 type notifierSettings struct {
 	entrypointBufferSize    uint
+	notifierBufferSizes     map[string]uint
+	notifierQueueSize       uint
 	notifierTimeoutDuration time.Duration
+	overflowPolicy          OverflowPolicy
+	dropCounter             *DropCounter
 
 	onReliableNotifierFailure func(error)
 	onNotifierTimeout         func(string)
@@ -30,12 +39,11 @@ type ReliableNotifierOpt func(*notifierSettings)
 // given event notifier. In the situation the notifierFn panics, the worker gets
 // restarted.
 func newNotifierWorker(
-	_ notifierSettings,
+	settings notifierSettings,
 	name string,
 	notifierFn s.EventNotifier,
 ) (chan s.Event, s.Node) {
-	// ch := make(chan s.Event, settings.notifierBufferSize)
-	ch := make(chan s.Event)
+	ch := make(chan s.Event, settings.notifierBufferSizes[name])
 	return ch, s.NewWorker(
 		name,
 		func(ctx context.Context) error {
@@ -71,32 +79,109 @@ func newNotifierSubTree(
 	return notifierTree, notifierChans
 }
 
+// notifierQueue holds the events that are waiting to be delivered to a single
+// notifier
+type notifierQueue struct {
+	name       string
+	queueCh    chan s.Event
+	notifierCh chan s.Event
+}
+
+// runNotifierDispatcher delivers the events of the given notifierQueue to its
+// notifier, one at a time. With the OverflowBlock policy, a notifier that is not
+// ready to receive an event within the notifier timeout gets the event skipped;
+// with the other policies, the dispatcher waits on the notifier, and the events
+// that do not fit on the queue get dropped by the policy.
+func runNotifierDispatcher(
+	ctx context.Context,
+	settings notifierSettings,
+	queue notifierQueue,
+) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case ev := <-queue.queueCh:
+			if settings.overflowPolicy != OverflowBlock {
+				select {
+				case <-ctx.Done():
+					return
+				case queue.notifierCh <- ev:
+				}
+				continue
+			}
+
+			timer := time.NewTimer(settings.notifierTimeoutDuration)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+
+			case <-timer.C:
+				settings.dropCounter.add(queue.name, 1)
+				settings.onNotifierTimeout(queue.name)
+
+			case queue.notifierCh <- ev:
+				timer.Stop()
+			}
+		}
+	}
+}
+
 // runEntrypointListener listens to a channel for events, and it then broadcast
 // in a non-blocking fashion the same event across multiple workers.
+//
+// Each notifier gets a dedicated queue and dispatcher goroutine, so a slow or
+// dead notifier never delays the delivery of events to the other notifiers.
 func runEntrypointListener(
 	ctx context.Context,
 	settings notifierSettings,
 	entrypointCh chan s.Event,
 	notifierChans map[string](chan s.Event),
 ) error {
+	queues := make([]notifierQueue, 0, len(notifierChans))
+	for name, ch := range notifierChans {
+		queues = append(queues, notifierQueue{
+			name:       name,
+			queueCh:    make(chan s.Event, settings.notifierQueueSize),
+			notifierCh: ch,
+		})
+	}
+
+	// with the OverflowBlock policy, a full queue means the notifier is not
+	// keeping up with the events, we skip the event rather than waiting on it
+	queuePolicy := settings.overflowPolicy
+	if queuePolicy == OverflowBlock {
+		queuePolicy = OverflowDropNewest
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for _, queue := range queues {
+		wg.Add(1)
+		go func(queue notifierQueue) {
+			defer wg.Done()
+			runNotifierDispatcher(ctx, settings, queue)
+		}(queue)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 
 		case ev := <-entrypointCh:
-			for name, ch := range notifierChans {
-				notifyCtx, stopTimer := context.WithTimeout(
-					context.Background(),
-					settings.notifierTimeoutDuration,
-				)
-				select {
-				case <-notifyCtx.Done():
-					settings.onNotifierTimeout(name)
-
-				case ch <- ev:
+			for _, queue := range queues {
+				dropped := sendEvent(queuePolicy, queue.queueCh, ev)
+				if dropped == 0 {
+					continue
+				}
+				settings.dropCounter.add(queue.name, dropped)
+				if settings.overflowPolicy == OverflowBlock {
+					settings.onNotifierTimeout(queue.name)
 				}
-				stopTimer()
 			}
 		}
 	}
@@ -104,7 +189,8 @@ func runEntrypointListener(
 
 // WithOnNotifierTimeout sets callback that gets executed when a given notifier
 // is so slow to get an event that it gets skipped. You need to ensure the given
-// callback does not block.
+// callback does not block. The callback may be called concurrently for
+// different notifiers.
 func WithOnNotifierTimeout(cb func(string)) ReliableNotifierOpt {
 	return func(settings *notifierSettings) {
 		settings.onNotifierTimeout = cb
@@ -120,6 +206,56 @@ func WithOnReliableNotifierFailure(cb func(error)) ReliableNotifierOpt {
 	}
 }
 
+// WithEntrypointBufferSize sets the size of the buffer that holds the events
+// reported by the supervision system before they get broadcasted to the
+// notifiers (defaults to 0, the supervision system waits until the event is
+// taken)
+func WithEntrypointBufferSize(size uint) ReliableNotifierOpt {
+	return func(settings *notifierSettings) {
+		settings.entrypointBufferSize = size
+	}
+}
+
+// WithNotifierBufferSize sets the size of the buffer that holds the events for
+// the notifier with the given name (defaults to 0, the notifier must be ready
+// to receive the event when it gets broadcasted).
+func WithNotifierBufferSize(name string, size uint) ReliableNotifierOpt {
+	return func(settings *notifierSettings) {
+		if settings.notifierBufferSizes == nil {
+			settings.notifierBufferSizes = make(map[string]uint)
+		}
+		settings.notifierBufferSizes[name] = size
+	}
+}
+
+// WithNotifierQueueSize sets the number of events that can wait to be
+// delivered to each notifier (defaults to 100). Events that do not fit on the
+// queue of a notifier are handled by the OverflowPolicy; with OverflowBlock they
+// get skipped, and the WithOnNotifierTimeout callback is called.
+func WithNotifierQueueSize(size uint) ReliableNotifierOpt {
+	return func(settings *notifierSettings) {
+		settings.notifierQueueSize = size
+	}
+}
+
+// WithOverflowPolicy sets what happens with an event when the buffer of the
+// entrypoint or the queue of a notifier is full (defaults to OverflowBlock).
+// With OverflowDropOldest and OverflowDropNewest, the ReliableNotifier never
+// blocks the supervision system.
+func WithOverflowPolicy(policy OverflowPolicy) ReliableNotifierOpt {
+	return func(settings *notifierSettings) {
+		settings.overflowPolicy = policy
+	}
+}
+
+// WithDropCounter sets a DropCounter that keeps track of the number of events
+// each notifier did not receive
+func WithDropCounter(dc *DropCounter) ReliableNotifierOpt {
+	return func(settings *notifierSettings) {
+		settings.dropCounter = dc
+	}
+}
+
 // WithNotifierTimeout sets the maximum allowed time the reliable notifier is going to
 // wait for a notifier function to be ready to receive an event (defaults to 10 millis).
 func WithNotifierTimeout(ts time.Duration) ReliableNotifierOpt {
@@ -150,6 +286,7 @@ func NewReliableNotifier(
 	// default notifier settings
 	settings := notifierSettings{
 		entrypointBufferSize:      0,
+		notifierQueueSize:         defaultNotifierQueueSize,
 		notifierTimeoutDuration:   10 * time.Millisecond,
 		onReliableNotifierFailure: func(error) {},
 		onNotifierTimeout:         func(string) {},
@@ -161,8 +298,7 @@ func NewReliableNotifier(
 
 	// the entrypoint channel is built outside the "notifier supervision tree"
 	// given the chan is referenced from the outside.
-	// entrypointCh := make(chan s.Event, settings.entrypointBufferSize)
-	entrypointCh := make(chan s.Event)
+	entrypointCh := make(chan s.Event, settings.entrypointBufferSize)
 
 	//
 	reliableNotifierSpec := s.NewSupervisorSpec(
@@ -192,13 +328,39 @@ func NewReliableNotifier(
 		return nil, nil, fmt.Errorf("could not start reliable notifier: %w", startErr)
 	}
 
+	notifierNames := make([]string, 0, len(notifierFns))
+	for name := range notifierFns {
+		notifierNames = append(notifierNames, name)
+	}
+
+	// doneCtx is cancelled when the ReliableNotifier is terminated, from that
+	// point on the entrypoint is not read anymore
+	doneCtx, doneFn := context.WithCancel(context.Background())
+
 	// this is the eventNotifier that the main supervision tree is going to use to
 	// send notifications.
 	eventNotifier := func(ev s.Event) {
-		entrypointCh <- ev
+		if settings.overflowPolicy == OverflowBlock {
+			select {
+			case entrypointCh <- ev:
+			case <-doneCtx.Done():
+				// events reported after termination are not received by any notifier
+				for _, name := range notifierNames {
+					settings.dropCounter.add(name, 1)
+				}
+			}
+			return
+		}
+		// events discarded on the entrypoint are not received by any notifier
+		if dropped := sendEvent(settings.overflowPolicy, entrypointCh, ev); dropped > 0 {
+			for _, name := range notifierNames {
+				settings.dropCounter.add(name, dropped)
+			}
+		}
 	}
 
 	cancelFn := func() {
+		doneFn()
 		_ = reliableNotifier.Terminate()
 	}
 
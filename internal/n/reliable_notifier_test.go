@@ -4,6 +4,8 @@ package n_test
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -18,6 +20,8 @@ import (
 // will block until the given number of notifications have happened
 func newBlockingNotifier(total int32) (cap.EventNotifier, func()) {
 	doneCh := make(chan struct{})
+	var closeDoneOnce sync.Once
+	closeDone := func() { closeDoneOnce.Do(func() { close(doneCh) }) }
 	evCount := int32(0)
 	evNotifier := func(cap.Event) {
 		current := atomic.LoadInt32(&evCount)
@@ -25,7 +29,7 @@ func newBlockingNotifier(total int32) (cap.EventNotifier, func()) {
 			atomic.AddInt32(&evCount, 1)
 			return
 		}
-		close(doneCh)
+		closeDone()
 	}
 	doneSignal := func() {
 		<-doneCh
@@ -33,18 +37,21 @@ func newBlockingNotifier(total int32) (cap.EventNotifier, func()) {
 	return evNotifier, doneSignal
 }
 
-// newPanicBlockingNotifier creates an EventNotifier and a callback function
-// that will block until the given number of panic calls have happened
+// newPanicBlockingNotifier creates an EventNotifier that panics on the first
+// given number of calls, and a callback function that will block until the
+// given number of panic calls have happened
 func newPanicBlockingNotifier(total int32) (cap.EventNotifier, func()) {
 	doneCh := make(chan struct{})
 	evCount := int32(0)
 	evNotifier := func(ev cap.Event) {
-		current := atomic.LoadInt32(&evCount)
-		if current < total-1 {
-			atomic.AddInt32(&evCount, 1)
-			panic("this is taking down the tree")
+		current := atomic.AddInt32(&evCount, 1)
+		if current > total {
+			return
 		}
-		close(doneCh)
+		if current == total {
+			close(doneCh)
+		}
+		panic("this is taking down the tree")
 	}
 	doneSignal := func() {
 		<-doneCh
@@ -149,8 +156,12 @@ func TestReliableNotifierFailureCallback(t *testing.T) {
 	notifier1, done1 := newBlockingNotifier(int32(len(outEvents)))
 	notifier2, done2 := newBlockingNotifier(int32(len(outEvents)))
 
-	// build a callback function for event notifier errors
+	// build a callback function for event notifier errors; the callback may get
+	// called more than the expected times (e.g. when the notifiers sub-tree
+	// fails while the reliable notifier is terminated)
 	callbackDone := make(chan struct{})
+	var closeCallbackDoneOnce sync.Once
+	closeCallbackDone := func() { closeCallbackDoneOnce.Do(func() { close(callbackDone) }) }
 	errCount := int32(0)
 	errCallback := func(err error) {
 		current := atomic.LoadInt32(&errCount)
@@ -158,7 +169,7 @@ func TestReliableNotifierFailureCallback(t *testing.T) {
 			atomic.AddInt32(&errCount, 1)
 			return
 		}
-		close(callbackDone)
+		closeCallbackDone()
 	}
 
 	// create the reliable event notifier that broadcasts to notifiers created in
@@ -170,6 +181,9 @@ func TestReliableNotifierFailureCallback(t *testing.T) {
 			"panic":     panicEvNotifier,
 		},
 		cap.WithOnReliableNotifierFailure(errCallback),
+		// the notifiers get restarted with the panicking notifier, the timeout
+		// ensures they do not get events skipped while they restart
+		cap.WithNotifierTimeout(time.Second),
 	)
 	defer cancelEvNotifier()
 
@@ -222,14 +236,14 @@ func TestReliableNotifierSlowNotifier(t *testing.T) {
 	callbackCounter := int32(0)
 	timeoutCallback := func(name string) {
 		assert.Equal(t, "slow", name)
-		current := atomic.LoadInt32(&callbackCounter)
-		if current < expectedCallbackCalls-1 {
-			atomic.AddInt32(&callbackCounter, 1)
-			return
+		current := atomic.AddInt32(&callbackCounter, 1)
+		if current == expectedCallbackCalls {
+			close(callbacksDone)
 		}
-		close(callbacksDone)
 	}
 
+	var dropCounter cap.DropCounter
+
 	notifier1, done1 := newBlockingNotifier(int32(len(outEvents)))
 
 	// create the reliable event notifier that broadcasts to notifiers created in
@@ -241,7 +255,11 @@ func TestReliableNotifierSlowNotifier(t *testing.T) {
 		},
 		// use a very small timeout to make the test run fast
 		cap.WithNotifierTimeout(100*time.Microsecond),
+		// the buffer ensures the small timeout never skips events of the fast
+		// notifier
+		cap.WithNotifierBufferSize("notifier", uint(len(outEvents))),
 		cap.WithOnNotifierTimeout(timeoutCallback),
+		cap.WithDropCounter(&dropCounter),
 	)
 
 	// assert reliable notifier started without errors
@@ -268,6 +286,297 @@ func TestReliableNotifierSlowNotifier(t *testing.T) {
 	done1()
 	<-callbacksDone
 
+	// the skipped events are accounted as dropped
+	assert.Equal(t, uint64(expectedCallbackCalls), dropCounter.Get("slow"))
+	assert.Equal(t, uint64(0), dropCounter.Get("notifier"))
+
 	// this process is slow on unresponsive workers, so doing an async call for that
 	go cancelEvNotifier()
 }
+
+// buildEvents returns the given number of distinct events, these events are
+// collected from a supervision tree with many workers
+func buildEvents(t *testing.T, total int) []cap.Event {
+	nodes := make([]cap.Node, 0, total)
+	for i := 0; i < total; i++ {
+		nodes = append(nodes, WaitDoneWorker(fmt.Sprintf("child%d", i)))
+	}
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(nodes...),
+		[]cap.Opt{},
+		func(EventManager) {},
+	)
+	assert.NoError(t, err)
+	return events[:total]
+}
+
+// newStuckNotifier creates an EventNotifier that records the events it
+// receives; it gets stuck on the first event until the returned release
+// function is called. The second returned function blocks until the first
+// event is received.
+func newStuckNotifier() (cap.EventNotifier, func(), func(), func() []cap.Event) {
+	var mux sync.Mutex
+	var received []cap.Event
+
+	firstCh := make(chan struct{})
+	var closeFirstOnce sync.Once
+	closeFirst := func() { closeFirstOnce.Do(func() { close(firstCh) }) }
+	releaseCh := make(chan struct{})
+
+	evNotifier := func(ev cap.Event) {
+		mux.Lock()
+		received = append(received, ev)
+		mux.Unlock()
+		closeFirst()
+		<-releaseCh
+	}
+	waitFirst := func() { <-firstCh }
+	release := func() { close(releaseCh) }
+	getReceived := func() []cap.Event {
+		mux.Lock()
+		defer mux.Unlock()
+		return append(received[:0:0], received...)
+	}
+	return evNotifier, waitFirst, release, getReceived
+}
+
+// waitDropCount blocks until the DropCounter reports at least the given count
+// for the given notifier name
+func waitDropCount(t *testing.T, dc *cap.DropCounter, name string, count uint64) {
+	for i := 0; i < 100; i++ {
+		if dc.Get(name) >= count {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.FailNow(t, "drop count not reached", "want: %d, got: %d", count, dc.Get(name))
+}
+
+// waitSettled blocks until every one of the given events was either received
+// or dropped by the notifier with the given name
+func waitSettled(
+	t *testing.T,
+	dc *cap.DropCounter,
+	name string,
+	getReceived func() []cap.Event,
+	total int,
+) []cap.Event {
+	for i := 0; i < 100; i++ {
+		received := getReceived()
+		if len(received)+int(dc.Get(name)) == total {
+			return received
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.FailNow(t, "events were not settled")
+	return nil
+}
+
+// assertSubsequence asserts the given received events keep the order of the
+// given sent events
+func assertSubsequence(t *testing.T, sent []cap.Event, received []cap.Event) {
+	ix := 0
+	for _, ev := range sent {
+		if ix < len(received) && assert.ObjectsAreEqual(ev, received[ix]) {
+			ix++
+		}
+	}
+	assert.Equal(t, len(received), ix, "received events are out of order")
+}
+
+// waitReceived blocks until the given function returns the given number of
+// events
+func waitReceived(t *testing.T, getReceived func() []cap.Event, count int) []cap.Event {
+	for i := 0; i < 100; i++ {
+		received := getReceived()
+		if len(received) == count {
+			return received
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.FailNow(t, "events were not received")
+	return nil
+}
+
+// TestReliableNotifierOverflowPolicy verifies the events that are kept when the
+// queue of a notifier is full
+func TestReliableNotifierOverflowPolicy(t *testing.T) {
+	events := buildEvents(t, 10)
+
+	testCases := []struct {
+		policy cap.OverflowPolicy
+		// the events that must be received at the beginning and at the end of the
+		// delivery
+		head []cap.Event
+		tail []cap.Event
+	}{
+		{cap.OverflowDropNewest, []cap.Event{events[0], events[1], events[2]}, []cap.Event{}},
+		{cap.OverflowDropOldest, []cap.Event{events[0]}, []cap.Event{events[8], events[9]}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			stuckNotifier, waitFirst, release, getReceived := newStuckNotifier()
+			var dropCounter cap.DropCounter
+
+			evNotifier, cancelEvNotifier, err := cap.NewReliableNotifier(
+				map[string]cap.EventNotifier{"stuck": stuckNotifier},
+				cap.WithEntrypointBufferSize(uint(len(events))),
+				cap.WithNotifierQueueSize(2),
+				cap.WithOverflowPolicy(tc.policy),
+				cap.WithDropCounter(&dropCounter),
+			)
+			assert.NoError(t, err)
+			defer cancelEvNotifier()
+
+			evNotifier(events[0])
+			waitFirst()
+
+			// the notifier is stuck on the first event, only two events fit in its
+			// queue, and its dispatcher may hold another one
+			for _, ev := range events[1:] {
+				evNotifier(ev)
+			}
+			waitDropCount(t, &dropCounter, "stuck", uint64(len(events)-4))
+
+			release()
+			received := waitSettled(t, &dropCounter, "stuck", getReceived, len(events))
+			assert.True(t, len(received) <= 4)
+			assertSubsequence(t, events, received)
+			assert.Equal(t, tc.head, received[:len(tc.head)])
+			assert.Equal(t, tc.tail, received[len(received)-len(tc.tail):])
+		})
+	}
+}
+
+// TestReliableNotifierDropDoesNotBlock verifies that a ReliableNotifier with a
+// drop OverflowPolicy never blocks its caller, even when a notifier is stuck
+func TestReliableNotifierDropDoesNotBlock(t *testing.T) {
+	events := buildEvents(t, 10)
+	stuckNotifier, _, release, _ := newStuckNotifier()
+	defer release()
+
+	var dropCounter cap.DropCounter
+
+	evNotifier, cancelEvNotifier, err := cap.NewReliableNotifier(
+		map[string]cap.EventNotifier{"stuck": stuckNotifier},
+		cap.WithOverflowPolicy(cap.OverflowDropNewest),
+		cap.WithDropCounter(&dropCounter),
+	)
+	assert.NoError(t, err)
+	defer func() { go cancelEvNotifier() }()
+
+	doneCh := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			evNotifier(events[i%len(events)])
+		}
+		close(doneCh)
+	}()
+
+	select {
+	case <-doneCh:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "reliable notifier blocked its caller")
+	}
+
+	// at most one event was received, every other event got dropped either on
+	// the entrypoint or on the notifier
+	assert.True(t, dropCounter.Get("stuck") >= 99)
+}
+
+// TestReliableNotifierTerminatedDoesNotBlock verifies that a ReliableNotifier
+// with the OverflowBlock policy does not block its caller once it has been
+// terminated
+func TestReliableNotifierTerminatedDoesNotBlock(t *testing.T) {
+	events := buildEvents(t, 1)
+	recordingNotifier, getReceived := newRecordingNotifier()
+
+	var dropCounter cap.DropCounter
+
+	evNotifier, cancelEvNotifier, err := cap.NewReliableNotifier(
+		map[string]cap.EventNotifier{"recording": recordingNotifier},
+		cap.WithDropCounter(&dropCounter),
+	)
+	assert.NoError(t, err)
+	cancelEvNotifier()
+
+	doneCh := make(chan struct{})
+	go func() {
+		evNotifier(events[0])
+		close(doneCh)
+	}()
+
+	select {
+	case <-doneCh:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "terminated reliable notifier blocked its caller")
+	}
+
+	assert.Empty(t, getReceived())
+	assert.Equal(t, map[string]uint64{"recording": 1}, dropCounter.Snapshot())
+}
+
+// newRecordingNotifier creates an EventNotifier that records the events it
+// receives
+func newRecordingNotifier() (cap.EventNotifier, func() []cap.Event) {
+	var mux sync.Mutex
+	var received []cap.Event
+
+	evNotifier := func(ev cap.Event) {
+		mux.Lock()
+		defer mux.Unlock()
+		received = append(received, ev)
+	}
+	getReceived := func() []cap.Event {
+		mux.Lock()
+		defer mux.Unlock()
+		return append(received[:0:0], received...)
+	}
+	return evNotifier, getReceived
+}
+
+// TestReliableNotifierIndependentDelivery verifies that a stuck notifier does
+// not delay the delivery of events to other notifiers, and that every notifier
+// receives its events in order
+func TestReliableNotifierIndependentDelivery(t *testing.T) {
+	events := buildEvents(t, 10)
+	stuckNotifier, waitFirst, release, getStuckReceived := newStuckNotifier()
+	fastNotifier, getFastReceived := newRecordingNotifier()
+
+	var dropCounter cap.DropCounter
+
+	evNotifier, cancelEvNotifier, err := cap.NewReliableNotifier(
+		map[string]cap.EventNotifier{
+			"stuck": stuckNotifier,
+			"fast":  fastNotifier,
+		},
+		// a sequential delivery would take at least 9 seconds for the fast
+		// notifier to receive all the events
+		cap.WithNotifierTimeout(time.Second),
+		cap.WithDropCounter(&dropCounter),
+	)
+	assert.NoError(t, err)
+	defer cancelEvNotifier()
+
+	evNotifier(events[0])
+	waitFirst()
+
+	start := time.Now()
+	for _, ev := range events[1:] {
+		evNotifier(ev)
+	}
+
+	received := waitReceived(t, getFastReceived, len(events))
+	assert.Equal(t, events, received)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+
+	// the stuck notifier gets the queued events once it is released
+	release()
+	received = waitReceived(t, getStuckReceived, len(events))
+	assert.Equal(t, events, received)
+	assert.Equal(t, map[string]uint64{}, dropCounter.Snapshot())
+}
@@ -0,0 +1,150 @@
+package s_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+// findAbandonedProcess returns the abandoned process with the given runtime
+// name, if any
+func findAbandonedProcess(name string) (cap.AbandonedProcess, bool) {
+	for _, ap := range cap.AbandonedProcesses() {
+		if ap.GetRuntimeName() == name {
+			return ap, true
+		}
+	}
+	return cap.AbandonedProcess{}, false
+}
+
+func TestAbandonedProcess(t *testing.T) {
+	releaseCh := make(chan struct{})
+
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	rootSpec := cap.NewSupervisorSpec(
+		"abandoned",
+		cap.WithNodes(
+			hangingWorker(
+				"child1",
+				releaseCh,
+				cap.WithShutdown(cap.Timeout(10*time.Millisecond)),
+				cap.WithCaptureAbandonedStack(),
+			),
+		),
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+
+	sup, err := rootSpec.Start(ctx)
+	assert.NoError(t, err)
+
+	evIt := evManager.Iterator()
+	evIt.SkipTill(SupervisorStarted("abandoned"))
+
+	abandonTime := time.Now()
+	err = sup.TerminateChild(ctx, "abandoned/child1")
+
+	// the failure of the worker is reported with a ShutdownTimeoutError
+	var timeoutErr *cap.ShutdownTimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, "abandoned/child1", timeoutErr.GetNodeName())
+	assert.Equal(t, 10*time.Millisecond, timeoutErr.GetTimeout())
+
+	ap, ok := findAbandonedProcess("abandoned/child1")
+	assert.True(t, ok)
+	assert.False(t, ap.GetAbandonedAt().Before(abandonTime))
+	assert.True(t, bytes.Contains(ap.GetStack(), []byte("hangingWorker")))
+
+	// the abandoned worker finishes in the background, while the supervisor is
+	// running
+	close(releaseCh)
+	evIt.SkipTill(WorkerAbandonedExited("abandoned/child1"))
+
+	assert.NoError(t, sup.Terminate())
+	evIt.SkipTill(SupervisorTerminated("abandoned"))
+
+	AssertExactMatch(t, evManager.Snapshot(),
+		[]EventP{
+			WorkerStarted("abandoned/child1"),
+			SupervisorStarted("abandoned"),
+			WorkerFailedWith("abandoned/child1", "child shutdown timeout"),
+			WorkerAbandoned("abandoned/child1"),
+			WorkerAbandonedExited("abandoned/child1"),
+			SupervisorTerminated("abandoned"),
+		},
+	)
+
+	// the registry forgets about abandoned processes that finished
+	assertAbandonedProcessRemoved(t, "abandoned/child1")
+}
+
+func TestAbandonedProcessOnSupervisorTermination(t *testing.T) {
+	releaseCh := make(chan struct{})
+
+	// events are collected on a buffered channel, given the supervisor is not
+	// waiting for the notifier to finish
+	evCh := make(chan cap.Event, 10)
+
+	rootSpec := cap.NewSupervisorSpec(
+		"abandoned-on-termination",
+		cap.WithNodes(
+			hangingWorker(
+				"child1",
+				releaseCh,
+				cap.WithShutdown(cap.Timeout(10*time.Millisecond)),
+			),
+		),
+		cap.WithNotifier(func(ev cap.Event) { evCh <- ev }),
+	)
+
+	sup, err := rootSpec.Start(context.TODO())
+	assert.NoError(t, err)
+
+	err = sup.Terminate()
+	assert.Error(t, err)
+
+	_, ok := findAbandonedProcess("abandoned-on-termination/child1")
+	assert.True(t, ok)
+
+	// the abandoned worker finishes after its supervisor is done, the exit of
+	// the worker is not reported
+	close(releaseCh)
+	assertAbandonedProcessRemoved(t, "abandoned-on-termination/child1")
+
+	var events []cap.Event
+	for len(evCh) > 0 {
+		events = append(events, <-evCh)
+	}
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("abandoned-on-termination/child1"),
+			SupervisorStarted("abandoned-on-termination"),
+			WorkerFailedWith("abandoned-on-termination/child1", "child shutdown timeout"),
+			WorkerAbandoned("abandoned-on-termination/child1"),
+			SupervisorFailed("abandoned-on-termination"),
+		},
+	)
+}
+
+// assertAbandonedProcessRemoved waits for the abandoned process with the given
+// runtime name to be removed from the abandoned registry
+func assertAbandonedProcessRemoved(t *testing.T, name string) {
+	for i := 0; i < 100; i++ {
+		if _, ok := findAbandonedProcess(name); !ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Fail(t, "abandoned worker is still registered")
+}
@@ -2,6 +2,8 @@ package s_test
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/capatazlib/go-capataz/cap"
@@ -96,3 +98,72 @@ func TestCapturePanic(t *testing.T) {
 		)
 	})
 }
+
+func TestCapturePanicError(t *testing.T) {
+	panicValErr := errors.New("panic value")
+
+	testCases := []struct {
+		name     string
+		panicVal interface{}
+		errMsg   string
+	}{
+		{name: "non-error value", panicVal: "boom", errMsg: "panic error: boom"},
+		{name: "error value", panicVal: panicValErr, errMsg: "panic value"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var panicked bool
+			child1 := cap.NewWorker(
+				"child1",
+				func(ctx context.Context) error {
+					// only the first run of the worker panics
+					if !panicked {
+						panicked = true
+						panic(tc.panicVal)
+					}
+					<-ctx.Done()
+					return nil
+				},
+			)
+
+			events, err := ObserveSupervisor(
+				context.TODO(),
+				"root",
+				cap.WithNodes(child1),
+				[]cap.Opt{},
+				func(em EventManager) {
+					evIt := em.Iterator()
+					evIt.SkipTill(WorkerFailed("root/child1"))
+					evIt.SkipTill(WorkerStarted("root/child1"))
+				},
+			)
+
+			assert.NoError(t, err)
+
+			AssertExactMatch(t, events,
+				[]EventP{
+					WorkerStarted("root/child1"),
+					SupervisorStarted("root"),
+					WorkerFailedWith("root/child1", tc.errMsg),
+					WorkerStarted("root/child1"),
+					WorkerTerminated("root/child1"),
+					SupervisorTerminated("root"),
+				},
+			)
+
+			// the failure event holds the panic value and the stack of the panic
+			var panicErr *cap.PanicError
+			failureErr := events[2].Err()
+			assert.True(t, errors.As(failureErr, &panicErr))
+			assert.Equal(t, tc.panicVal, panicErr.GetValue())
+			assert.True(t, strings.Contains(string(panicErr.GetStack()), "TestCapturePanicError"))
+
+			// errors given to panic are reachable
+			assert.Equal(t, tc.panicVal == panicValErr, errors.Is(failureErr, panicValErr))
+
+			explanation := cap.ExplainError(failureErr)
+			assert.True(t, strings.HasPrefix(explanation, tc.errMsg+"\n\ngoroutine "), explanation)
+		})
+	}
+}
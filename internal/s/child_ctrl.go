@@ -0,0 +1,409 @@
+package s
+
+// This file contains the implementation of the public API that allows clients
+// to start, terminate, restart and delete individual nodes of a running
+// Supervisor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/capatazlib/go-capataz/internal/c"
+)
+
+// findChildSpec returns the spec of the child with the given name
+func findChildSpec(specChildren []c.ChildSpec, chName string) (int, c.ChildSpec, bool) {
+	for i, chSpec := range specChildren {
+		if chSpec.GetName() == chName {
+			return i, chSpec, true
+		}
+	}
+	return 0, c.ChildSpec{}, false
+}
+
+// isChildRunning returns true if the child with the given name has a goroutine
+// that is running (or is about to be restarted)
+func isChildRunning(
+	supChildren map[string]c.Child,
+	supScheduler *restartScheduler,
+	chName string,
+) bool {
+	_, ok := supChildren[chName]
+	return ok && !supScheduler.isPending(supChildren, chName)
+}
+
+// sendNodeResult sends the result of a node operation without blocking
+func sendNodeResult(resultChan chan<- error, err error) {
+	select {
+	case resultChan <- err:
+	default:
+	}
+}
+
+type lookupSubtreeResult struct {
+	ctrl supervisorCtrl
+	err  error
+}
+
+// lookupSubtreeMsg is a message sent from clients to get the supervisorCtrl of
+// a running sub-tree of a supervisor.
+type lookupSubtreeMsg struct {
+	nodeName        string
+	nodeRuntimeName string
+	resultChan      chan<- lookupSubtreeResult
+}
+
+func (lsm lookupSubtreeMsg) processMsg(
+	_ context.Context,
+	_ EventNotifier,
+	_ SupervisorSpec,
+	specChildren []c.ChildSpec,
+	_ string,
+	supChildren map[string]c.Child,
+	_ chan c.ChildNotification,
+	supScheduler *restartScheduler,
+) ([]c.ChildSpec, map[string]c.Child) {
+	// REMEMBER: WE ARE RUNNING THIS CODE IN THE SUPERVISOR THREAD
+
+	var result lookupSubtreeResult
+	_, chSpec, ok := findChildSpec(specChildren, lsm.nodeName)
+
+	if !ok || !isChildRunning(supChildren, supScheduler, lsm.nodeName) {
+		result.err = &NodeNotFoundError{nodeName: lsm.nodeRuntimeName}
+	} else if ctrl, isSubtree := newSubtreeCtrl(chSpec, supChildren[lsm.nodeName]); !isSubtree {
+		result.err = &NodeNotFoundError{nodeName: lsm.nodeRuntimeName}
+	} else {
+		result.ctrl = ctrl
+	}
+
+	// do not block waiting for a read
+	select {
+	case lsm.resultChan <- result:
+	default:
+	}
+
+	return specChildren, supChildren
+}
+
+var _ ctrlMsg = lookupSubtreeMsg{}
+
+// stopChildMsg is a message sent from clients to tell a supervisor to terminate
+// one of its children. The child spec is kept, so the child may be restarted
+// later with a restartChildMsg.
+type stopChildMsg struct {
+	nodeName        string
+	nodeRuntimeName string
+	resultChan      chan<- error
+}
+
+func (scm stopChildMsg) processMsg(
+	_ context.Context,
+	evNotifier EventNotifier,
+	spec SupervisorSpec,
+	specChildren []c.ChildSpec,
+	_ string,
+	supChildren map[string]c.Child,
+	_ chan c.ChildNotification,
+	_ *restartScheduler,
+) ([]c.ChildSpec, map[string]c.Child) {
+	// REMEMBER: WE ARE RUNNING THIS CODE IN THE SUPERVISOR THREAD
+
+	if _, _, ok := findChildSpec(specChildren, scm.nodeName); !ok {
+		sendNodeResult(scm.resultChan, &NodeNotFoundError{nodeName: scm.nodeRuntimeName})
+		return specChildren, supChildren
+	}
+
+	ch, ok := supChildren[scm.nodeName]
+	if !ok {
+		// the child is already terminated
+		sendNodeResult(scm.resultChan, nil)
+		return specChildren, supChildren
+	}
+
+	// we remove the child from the runtime children to avoid restarting it (or
+	// shutting it down on supervisor termination). If the child had a pending
+	// restart, it is going to be discarded.
+	delete(supChildren, scm.nodeName)
+	terminateErr := terminateChildNode(evNotifier, spec.inbox, ch)
+	sendNodeResult(scm.resultChan, terminateErr)
+
+	return specChildren, supChildren
+}
+
+var _ ctrlMsg = stopChildMsg{}
+
+// restartChildMsg is a message sent from clients to tell a supervisor to start
+// again a child that was terminated.
+type restartChildMsg struct {
+	nodeName        string
+	nodeRuntimeName string
+	resultChan      chan<- error
+}
+
+func (rcm restartChildMsg) processMsg(
+	supCtx context.Context,
+	_ EventNotifier,
+	spec SupervisorSpec,
+	specChildren []c.ChildSpec,
+	supRuntimeName string,
+	supChildren map[string]c.Child,
+	supNotifyChan chan c.ChildNotification,
+	supScheduler *restartScheduler,
+) ([]c.ChildSpec, map[string]c.Child) {
+	// REMEMBER: WE ARE RUNNING THIS CODE IN THE SUPERVISOR THREAD
+
+	_, chSpec, ok := findChildSpec(specChildren, rcm.nodeName)
+	if !ok {
+		sendNodeResult(rcm.resultChan, &NodeNotFoundError{nodeName: rcm.nodeRuntimeName})
+		return specChildren, supChildren
+	}
+
+	if isChildRunning(supChildren, supScheduler, rcm.nodeName) {
+		sendNodeResult(rcm.resultChan, &NodeRunningError{nodeName: rcm.nodeRuntimeName})
+		return specChildren, supChildren
+	}
+
+	ch, startErr := startChildNode(supCtx, spec, supRuntimeName, supNotifyChan, chSpec, 0)
+	if startErr != nil {
+		sendNodeResult(rcm.resultChan, startErr)
+		return specChildren, supChildren
+	}
+
+	// if the child is waiting for a delayed restart, we keep its restart
+	// bookkeeping; the pending restart is going to be discarded.
+	if prevCh, ok := supChildren[rcm.nodeName]; ok {
+		ch = ch.WithRestartStateOf(prevCh)
+	}
+	supChildren[rcm.nodeName] = ch
+	sendNodeResult(rcm.resultChan, nil)
+
+	return specChildren, supChildren
+}
+
+var _ ctrlMsg = restartChildMsg{}
+
+// deleteChildMsg is a message sent from clients to tell a supervisor to remove
+// the spec of a child that was terminated.
+type deleteChildMsg struct {
+	nodeName        string
+	nodeRuntimeName string
+	resultChan      chan<- error
+}
+
+func (dcm deleteChildMsg) processMsg(
+	_ context.Context,
+	_ EventNotifier,
+	_ SupervisorSpec,
+	specChildren []c.ChildSpec,
+	_ string,
+	supChildren map[string]c.Child,
+	_ chan c.ChildNotification,
+	supScheduler *restartScheduler,
+) ([]c.ChildSpec, map[string]c.Child) {
+	// REMEMBER: WE ARE RUNNING THIS CODE IN THE SUPERVISOR THREAD
+
+	i, _, ok := findChildSpec(specChildren, dcm.nodeName)
+	if !ok {
+		sendNodeResult(dcm.resultChan, &NodeNotFoundError{nodeName: dcm.nodeRuntimeName})
+		return specChildren, supChildren
+	}
+
+	if isChildRunning(supChildren, supScheduler, dcm.nodeName) {
+		sendNodeResult(dcm.resultChan, &NodeRunningError{nodeName: dcm.nodeRuntimeName})
+		return specChildren, supChildren
+	}
+
+	// we do not modify the given slice in place, given it may be shared with
+	// other references of the children specs
+	newSpecChildren := make([]c.ChildSpec, 0, len(specChildren)-1)
+	newSpecChildren = append(newSpecChildren, specChildren[:i]...)
+	newSpecChildren = append(newSpecChildren, specChildren[i+1:]...)
+
+	// a child waiting for a delayed restart is discarded
+	delete(supChildren, dcm.nodeName)
+	sendNodeResult(dcm.resultChan, nil)
+
+	return newSpecChildren, supChildren
+}
+
+var _ ctrlMsg = deleteChildMsg{}
+
+// resolveNodeSupervisor finds the supervisorCtrl of the supervisor that
+// supervises the node with the given runtime name; it also returns the spec
+// name of the node.
+func (sup Supervisor) resolveNodeSupervisor(
+	ctx context.Context,
+	runtimeName string,
+) (supervisorCtrl, string, error) {
+	// REMEMBER: WE ARE RUNNING ON THE CLIENT API THREAD
+
+	prefix := sup.runtimeName + NodeSepToken
+	if !strings.HasPrefix(runtimeName, prefix) {
+		return supervisorCtrl{}, "", &NodeNotFoundError{nodeName: runtimeName}
+	}
+
+	nodeNames := strings.Split(strings.TrimPrefix(runtimeName, prefix), NodeSepToken)
+	ctrl := supervisorCtrl{ctrlChan: sup.ctrlCh}
+	subtreeRuntimeName := sup.runtimeName
+
+	for _, subtreeName := range nodeNames[:len(nodeNames)-1] {
+		subtreeRuntimeName = strings.Join(
+			[]string{subtreeRuntimeName, subtreeName}, NodeSepToken,
+		)
+		resultChan := make(chan lookupSubtreeResult, 1)
+		msg := lookupSubtreeMsg{
+			nodeName:        subtreeName,
+			nodeRuntimeName: subtreeRuntimeName,
+			resultChan:      resultChan,
+		}
+		if err := ctrl.send(ctx, msg); err != nil {
+			return supervisorCtrl{}, "", err
+		}
+		select {
+		case result := <-resultChan:
+			if result.err != nil {
+				return supervisorCtrl{}, "", result.err
+			}
+			ctrl = result.ctrl
+		case <-ctx.Done():
+			return supervisorCtrl{}, "", fmt.Errorf("could not get a response from supervisor: %w", ctx.Err())
+		}
+	}
+
+	return ctrl, nodeNames[len(nodeNames)-1], nil
+}
+
+// sendNodeMsg resolves the supervisor of the node with the given runtime name
+// and sends the message created with buildMsg to it. It blocks until the
+// supervisor processes the message or the given context is done.
+func (sup Supervisor) sendNodeMsg(
+	ctx context.Context,
+	runtimeName string,
+	buildMsg func(string, chan<- error) ctrlMsg,
+) error {
+	// REMEMBER: WE ARE RUNNING ON THE CLIENT API THREAD
+
+	ctrl, nodeName, err := sup.resolveNodeSupervisor(ctx, runtimeName)
+	if err != nil {
+		return err
+	}
+
+	// we initialize the resultChan with a buffer of 1, we may store the result
+	// before the client is ready to read it.
+	resultChan := make(chan error, 1)
+	if err := ctrl.send(ctx, buildMsg(nodeName, resultChan)); err != nil {
+		return err
+	}
+
+	select {
+	case err = <-resultChan:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("could not get a response from supervisor: %w", ctx.Err())
+	}
+}
+
+// TerminateChild terminates the node with the given runtime name (e.g.
+// root/api/http-listener). The node is not going to be restarted by its
+// supervisor, but its specification is kept, so it can be started again with
+// RestartChild.
+//
+// If the runtime name does not belong to a node of this supervision tree, a
+// NodeNotFoundError is returned.
+func (sup Supervisor) TerminateChild(ctx context.Context, runtimeName string) error {
+	return sup.sendNodeMsg(
+		ctx,
+		runtimeName,
+		func(nodeName string, resultChan chan<- error) ctrlMsg {
+			return stopChildMsg{
+				nodeName:        nodeName,
+				nodeRuntimeName: runtimeName,
+				resultChan:      resultChan,
+			}
+		},
+	)
+}
+
+// RestartChild starts a node with the given runtime name (e.g.
+// root/api/http-listener) that was terminated with TerminateChild.
+//
+// If the runtime name does not belong to a node of this supervision tree, a
+// NodeNotFoundError is returned. If the node is running, a NodeRunningError is
+// returned.
+func (sup Supervisor) RestartChild(ctx context.Context, runtimeName string) error {
+	return sup.sendNodeMsg(
+		ctx,
+		runtimeName,
+		func(nodeName string, resultChan chan<- error) ctrlMsg {
+			return restartChildMsg{
+				nodeName:        nodeName,
+				nodeRuntimeName: runtimeName,
+				resultChan:      resultChan,
+			}
+		},
+	)
+}
+
+// DeleteChild removes the specification of a terminated node with the given
+// runtime name (e.g. root/api/http-listener) from its supervisor.
+//
+// Note that when the supervisor of the node gets restarted, it builds its
+// children from its original specification.
+//
+// If the runtime name does not belong to a node of this supervision tree, a
+// NodeNotFoundError is returned. If the node is running, a NodeRunningError is
+// returned.
+func (sup Supervisor) DeleteChild(ctx context.Context, runtimeName string) error {
+	return sup.sendNodeMsg(
+		ctx,
+		runtimeName,
+		func(nodeName string, resultChan chan<- error) ctrlMsg {
+			return deleteChildMsg{
+				nodeName:        nodeName,
+				nodeRuntimeName: runtimeName,
+				resultChan:      resultChan,
+			}
+		},
+	)
+}
+
+// StartChild starts the given node as a new child of a running Supervisor. The
+// node specification is appended to the supervisor children, this means the
+// node is going to be included on the restarts of the supervisor strategy
+// (e.g. OneForAll), and it is going to be started and terminated after (or
+// before) its siblings, as specified by the supervisor Order.
+//
+// Note that when the supervisor gets restarted by its parent, it builds its
+// children from its original specification.
+//
+// If a sibling node with the same name already exists, a NodeExistsError is
+// returned. This function blocks until the node is started or the given
+// context is done.
+func (sup Supervisor) StartChild(ctx context.Context, node Node) error {
+	// REMEMBER: WE ARE RUNNING ON THE CLIENT API THREAD
+
+	// if the supervisor is kaput, return the error
+	if terminated, terminationErr := sup.GetCrashError(false); terminated {
+		return fmt.Errorf("supervisor already terminated: %w", terminationErr)
+	}
+
+	// we initialize the resultChan with a buffer of 1, we may store the result
+	// before the client is ready to read it.
+	resultChan := make(chan startChildResult, 1)
+	msg := startChildMsg{
+		node:       node,
+		resultChan: resultChan,
+	}
+
+	if err := sendCtrlMsg(ctx, sup.ctrlCh, msg); err != nil {
+		return err
+	}
+
+	select {
+	case result := <-resultChan:
+		return result.startErr
+	case <-ctx.Done():
+		return fmt.Errorf("could not get a response from supervisor: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,43 @@
+package s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminateChildOfTerminatedSubtree(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	sup, _ := startSubtreeTree(ctx, t)
+
+	ctrl, nodeName, err := sup.resolveNodeSupervisor(ctx, "root/subtree/worker")
+	require.NoError(t, err)
+	assert.Equal(t, "worker", nodeName)
+
+	require.NoError(t, sup.Terminate())
+
+	// messages to a terminated sub-tree do not block, even without a deadline
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ctrl.send(
+			context.Background(),
+			stopChildMsg{
+				nodeName:        nodeName,
+				nodeRuntimeName: "root/subtree/worker",
+				resultChan:      make(chan error, 1),
+			},
+		)
+	}()
+
+	select {
+	case err := <-errCh:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("message to a terminated sub-tree is blocked")
+	}
+}
@@ -0,0 +1,281 @@
+package s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestTerminateRestartDeleteChild(t *testing.T) {
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	spec := cap.NewSupervisorSpec(
+		"root",
+		cap.WithNodes(WaitDoneWorker("child1"), WaitDoneWorker("child2")),
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+
+	sup, err := spec.Start(ctx)
+	assert.NoError(t, err)
+
+	evIt := evManager.Iterator()
+	evIt.SkipTill(SupervisorStarted("root"))
+
+	// a running child cannot be deleted
+	err = sup.DeleteChild(ctx, "root/child1")
+	var runningErr *cap.NodeRunningError
+	assert.True(t, errors.As(err, &runningErr))
+
+	// a running child cannot be restarted
+	err = sup.RestartChild(ctx, "root/child1")
+	assert.True(t, errors.As(err, &runningErr))
+
+	assert.NoError(t, sup.TerminateChild(ctx, "root/child1"))
+	evIt.SkipTill(WorkerTerminated("root/child1"))
+
+	// terminating a terminated child is a no-op
+	assert.NoError(t, sup.TerminateChild(ctx, "root/child1"))
+
+	snapshot, err := sup.Inspect(ctx)
+	assert.NoError(t, err)
+	child1Snapshot, ok := snapshot.Find("root/child1")
+	assert.True(t, ok)
+	assert.Equal(t, cap.NodeStopped, child1Snapshot.GetState())
+
+	assert.NoError(t, sup.RestartChild(ctx, "root/child1"))
+	evIt.SkipTill(WorkerStarted("root/child1"))
+
+	assert.NoError(t, sup.TerminateChild(ctx, "root/child1"))
+	evIt.SkipTill(WorkerTerminated("root/child1"))
+
+	assert.NoError(t, sup.DeleteChild(ctx, "root/child1"))
+
+	snapshot, err = sup.Inspect(ctx)
+	assert.NoError(t, err)
+	_, ok = snapshot.Find("root/child1")
+	assert.False(t, ok)
+
+	// a deleted child is not part of the tree anymore
+	err = sup.RestartChild(ctx, "root/child1")
+	var notFoundErr *cap.NodeNotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	assert.NoError(t, sup.Terminate())
+	evIt.SkipTill(SupervisorTerminated("root"))
+
+	AssertExactMatch(t, evManager.Snapshot(),
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/child1"),
+			WorkerStarted("root/child1"),
+			WorkerTerminated("root/child1"),
+			WorkerTerminated("root/child2"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestTerminateChildOfSubtree(t *testing.T) {
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	subtree := cap.NewSupervisorSpec(
+		"subtree",
+		cap.WithNodes(WaitDoneWorker("child2"), WaitDoneWorker("child3")),
+	)
+
+	spec := cap.NewSupervisorSpec(
+		"root",
+		cap.WithNodes(WaitDoneWorker("child1"), cap.Subtree(subtree)),
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+
+	sup, err := spec.Start(ctx)
+	assert.NoError(t, err)
+
+	evIt := evManager.Iterator()
+	evIt.SkipTill(SupervisorStarted("root"))
+
+	assert.NoError(t, sup.TerminateChild(ctx, "root/subtree/child2"))
+	evIt.SkipTill(WorkerTerminated("root/subtree/child2"))
+
+	assert.NoError(t, sup.RestartChild(ctx, "root/subtree/child2"))
+	evIt.SkipTill(WorkerStarted("root/subtree/child2"))
+
+	assert.NoError(t, sup.Terminate())
+	evIt.SkipTill(SupervisorTerminated("root"))
+
+	AssertExactMatch(t, evManager.Snapshot(),
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/subtree/child2"),
+			WorkerStarted("root/subtree/child3"),
+			SupervisorStarted("root/subtree"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/subtree/child2"),
+			WorkerStarted("root/subtree/child2"),
+			WorkerTerminated("root/subtree/child3"),
+			WorkerTerminated("root/subtree/child2"),
+			SupervisorTerminated("root/subtree"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestChildCtrlUnknownNode(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	subtree := cap.NewSupervisorSpec(
+		"subtree",
+		cap.WithNodes(WaitDoneWorker("child2")),
+	)
+	spec := cap.NewSupervisorSpec(
+		"root",
+		cap.WithNodes(WaitDoneWorker("child1"), cap.Subtree(subtree)),
+	)
+
+	sup, err := spec.Start(ctx)
+	assert.NoError(t, err)
+
+	for _, name := range []string{
+		"child1",
+		"other/child1",
+		"root/unknown",
+		"root/child1/child2",
+		"root/subtree/unknown",
+		"root/unknown/child2",
+	} {
+		var notFoundErr *cap.NodeNotFoundError
+		err = sup.TerminateChild(ctx, name)
+		assert.True(t, errors.As(err, &notFoundErr), name)
+		err = sup.RestartChild(ctx, name)
+		assert.True(t, errors.As(err, &notFoundErr), name)
+		err = sup.DeleteChild(ctx, name)
+		assert.True(t, errors.As(err, &notFoundErr), name)
+	}
+
+	assert.NoError(t, sup.Terminate())
+}
+
+func TestStartChildOneForAll(t *testing.T) {
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	child1, failWorker1 := FailOnSignalWorker(
+		1, "child1", cap.WithRestart(cap.Permanent),
+	)
+
+	spec := cap.NewSupervisorSpec(
+		"root",
+		cap.WithNodes(child1),
+		cap.WithStrategy(cap.OneForAll),
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+
+	sup, err := spec.Start(ctx)
+	assert.NoError(t, err)
+
+	evIt := evManager.Iterator()
+	evIt.SkipTill(SupervisorStarted("root"))
+
+	tenant := cap.NewSupervisorSpec(
+		"tenant",
+		cap.WithNodes(WaitDoneWorker("child3")),
+	)
+
+	assert.NoError(t, sup.StartChild(ctx, WaitDoneWorker("child2")))
+	assert.NoError(t, sup.StartChild(ctx, cap.Subtree(tenant)))
+
+	// node names must be unique between siblings
+	err = sup.StartChild(ctx, WaitDoneWorker("child2"))
+	var existsErr *cap.NodeExistsError
+	assert.True(t, errors.As(err, &existsErr))
+
+	// the new children are restarted with their siblings
+	failWorker1(true /* done */)
+	evIt.SkipTill(WorkerFailed("root/child1"))
+	evIt.SkipTill(SupervisorStarted("root/tenant"))
+
+	assert.NoError(t, sup.Terminate())
+	evIt.SkipTill(SupervisorTerminated("root"))
+
+	AssertExactMatch(t, evManager.Snapshot(),
+		[]EventP{
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/tenant/child3"),
+			SupervisorStarted("root/tenant"),
+
+			WorkerFailed("root/child1"),
+			SupervisorRestartToleranceIncremented("root", 1),
+			SupervisorRestarting("root", "root/child1"),
+			WorkerTerminated("root/tenant/child3"),
+			SupervisorTerminated("root/tenant"),
+			WorkerTerminated("root/child2"),
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/tenant/child3"),
+			SupervisorStarted("root/tenant"),
+
+			WorkerTerminated("root/tenant/child3"),
+			SupervisorTerminated("root/tenant"),
+			WorkerTerminated("root/child2"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestStartChildRightToLeft(t *testing.T) {
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	spec := cap.NewSupervisorSpec(
+		"root",
+		cap.WithNodes(WaitDoneWorker("child1")),
+		cap.WithStartOrder(cap.RightToLeft),
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+
+	sup, err := spec.Start(ctx)
+	assert.NoError(t, err)
+
+	evIt := evManager.Iterator()
+	evIt.SkipTill(SupervisorStarted("root"))
+
+	assert.NoError(t, sup.StartChild(ctx, WaitDoneWorker("child2")))
+
+	assert.NoError(t, sup.Terminate())
+	evIt.SkipTill(SupervisorTerminated("root"))
+
+	AssertExactMatch(t, evManager.Snapshot(),
+		[]EventP{
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			WorkerStarted("root/child2"),
+			// RightToLeft terminates from left to right
+			WorkerTerminated("root/child1"),
+			WorkerTerminated("root/child2"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
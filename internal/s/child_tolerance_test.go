@@ -0,0 +1,270 @@
+package s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestChildToleranceGiveUp(t *testing.T) {
+	parentName := "root"
+	child1, failWorker1 := FailOnSignalWorker(
+		2,
+		"child1",
+		cap.WithRestart(cap.Permanent),
+		cap.WithTolerance(1, 10*time.Second),
+	)
+	child2 := WaitDoneWorker("child2")
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		parentName,
+		cap.WithNodes(child1, child2),
+		[]cap.Opt{
+			cap.WithRestartTolerance(10, 10*time.Second),
+		},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			// 1) first error is tolerated
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+
+			// 2) second error surpasses the child tolerance
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerRestartToleranceReached("root/child1"))
+		},
+	)
+
+	// the supervisor keeps running after giving up on the child
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+
+			WorkerFailed("root/child1"),
+			WorkerStarted("root/child1"),
+			// ^^^ 1) first error
+
+			WorkerFailed("root/child1"),
+			WorkerRestartToleranceReached("root/child1"),
+			// ^^^ 2) the child is not restarted again
+
+			WorkerTerminated("root/child2"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestChildToleranceGiveUpSiblingRestart(t *testing.T) {
+	testCases := []struct {
+		name     string
+		strategy cap.Opt
+		// restartEvents are the events of the child1 tolerated error restart
+		restartEvents []EventP
+	}{
+		{
+			"OneForAll",
+			cap.WithStrategy(cap.OneForAll),
+			[]EventP{
+				WorkerTerminated("root/child2"),
+				WorkerStarted("root/child2"),
+				WorkerStarted("root/child1"),
+			},
+		},
+		{
+			"RestForOne",
+			cap.WithStrategy(cap.RestForOne),
+			[]EventP{
+				WorkerStarted("root/child1"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parentName := "root"
+			child1, failWorker1 := FailOnSignalWorker(
+				2,
+				"child1",
+				cap.WithRestart(cap.Permanent),
+				cap.WithTolerance(1, 10*time.Second),
+			)
+			child2, failWorker2 := FailOnSignalWorker(
+				1,
+				"child2",
+				cap.WithRestart(cap.Permanent),
+			)
+
+			events, err := ObserveSupervisor(
+				context.TODO(),
+				parentName,
+				// child1 is started after child2, so that the RestForOne strategy
+				// restarts it when child2 fails
+				cap.WithNodes(child2, child1),
+				[]cap.Opt{
+					tc.strategy,
+					cap.WithRestartTolerance(10, 10*time.Second),
+				},
+				func(em EventManager) {
+					evIt := em.Iterator()
+					evIt.SkipTill(SupervisorStarted("root"))
+
+					// 1) first error is tolerated
+					failWorker1(false /* done */)
+					evIt.SkipTill(WorkerStarted("root/child1"))
+
+					// 2) second error surpasses the child tolerance
+					failWorker1(false /* done */)
+					evIt.SkipTill(WorkerRestartToleranceReached("root/child1"))
+
+					// 3) a sibling failure restarts the running children
+					failWorker2(true /* done */)
+					evIt.SkipTill(WorkerStarted("root/child2"))
+				},
+			)
+
+			assert.NoError(t, err)
+
+			expected := []EventP{
+				WorkerStarted("root/child2"),
+				WorkerStarted("root/child1"),
+				SupervisorStarted("root"),
+
+				WorkerFailed("root/child1"),
+			}
+			expected = append(expected, tc.restartEvents...)
+			// ^^^ 1) first error
+
+			AssertExactMatch(t, events,
+				append(expected,
+					WorkerFailed("root/child1"),
+					WorkerRestartToleranceReached("root/child1"),
+					// ^^^ 2) the child is not restarted again
+
+					WorkerFailed("root/child2"),
+					WorkerStarted("root/child2"),
+					// ^^^ 3) the child the supervisor gave up on stays down
+
+					WorkerTerminated("root/child2"),
+					SupervisorTerminated("root"),
+				),
+			)
+		})
+	}
+}
+
+func TestChildToleranceEscalate(t *testing.T) {
+	parentName := "root"
+	child1, failWorker1 := FailOnSignalWorker(
+		2,
+		"child1",
+		cap.WithRestart(cap.Permanent),
+		cap.WithTolerance(1, 10*time.Second),
+		cap.WithToleranceAction(cap.Escalate),
+	)
+	child2 := WaitDoneWorker("child2")
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		parentName,
+		cap.WithNodes(child1, child2),
+		[]cap.Opt{
+			cap.WithRestartTolerance(10, 10*time.Second),
+		},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerTerminated("root/child2"))
+		},
+	)
+
+	// the supervisor fails even though its own tolerance was not surpassed
+	assert.Error(t, err)
+
+	var restartErr *cap.SupervisorRestartError
+	assert.True(t, errors.As(err, &restartErr))
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+
+			WorkerFailed("root/child1"),
+			WorkerStarted("root/child1"),
+
+			WorkerFailed("root/child1"),
+			WorkerRestartToleranceReached("root/child1"),
+			WorkerTerminated("root/child2"),
+			SupervisorFailed("root"),
+		},
+	)
+}
+
+func TestChildToleranceSurvivesSiblingRestarts(t *testing.T) {
+	parentName := "root"
+	child1, failWorker1 := FailOnSignalWorker(
+		2,
+		"child1",
+		cap.WithRestart(cap.Permanent),
+		cap.WithTolerance(1, 10*time.Second),
+	)
+	child2, failWorker2 := FailOnSignalWorker(
+		1,
+		"child2",
+		cap.WithRestart(cap.Permanent),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		parentName,
+		cap.WithNodes(child1, child2),
+		[]cap.Opt{
+			cap.WithStrategy(cap.OneForAll),
+			cap.WithRestartTolerance(10, 10*time.Second),
+		},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/child2"))
+
+			// child1 gets restarted because of child2, its error count is kept
+			failWorker2(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child2"))
+
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerRestartToleranceReached("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertPartialMatch(t, events,
+		[]EventP{
+			WorkerFailed("root/child1"),
+			WorkerFailed("root/child2"),
+			WorkerFailed("root/child1"),
+			WorkerRestartToleranceReached("root/child1"),
+			WorkerTerminated("root/child2"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
@@ -0,0 +1,153 @@
+package s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/capatazlib/go-capataz/internal/c"
+)
+
+// dynChildrenState is the state shared between a supervisor that spawns
+// children at runtime (e.g. DynSupervisor) and its clients.
+type dynChildrenState struct {
+	mu        sync.Mutex
+	removedCh chan struct{}
+	draining  bool
+}
+
+// newDynChildrenState creates a new dynChildrenState
+func newDynChildrenState() *dynChildrenState {
+	return &dynChildrenState{
+		removedCh: make(chan struct{}),
+	}
+}
+
+// waitRemoval returns a channel that gets closed the next time a child is
+// removed from the supervisor. This function must be called before a request
+// is sent to the supervisor, otherwise, a notification may be lost.
+func (st *dynChildrenState) waitRemoval() <-chan struct{} {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.removedCh
+}
+
+// notifyRemoval wakes up all the clients waiting for a child to be removed from
+// the supervisor. This function must be called from the supervisor monitor
+// loop.
+func (st *dynChildrenState) notifyRemoval() {
+	if st == nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	close(st.removedCh)
+	st.removedCh = make(chan struct{})
+}
+
+// startDrain signals the supervisor that it must not spawn or restart children
+// anymore
+func (st *dynChildrenState) startDrain() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.draining = true
+}
+
+// isDraining returns true when the supervisor must not spawn or restart
+// children anymore
+func (st *dynChildrenState) isDraining() bool {
+	if st == nil {
+		return false
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.draining
+}
+
+// drainMsg is a message sent from clients to tell a supervisor to stop
+// spawning and restarting children.
+type drainMsg struct{}
+
+func (dm drainMsg) processMsg(
+	_ context.Context,
+	_ EventNotifier,
+	spec SupervisorSpec,
+	specChildren []c.ChildSpec,
+	_ string,
+	supChildren map[string]c.Child,
+	_ chan c.ChildNotification,
+	supScheduler *restartScheduler,
+) ([]c.ChildSpec, map[string]c.Child) {
+	// REMEMBER: WE ARE RUNNING THIS CODE IN THE SUPERVISOR THREAD
+
+	spec.dynChildren.startDrain()
+
+	// children waiting for a delayed restart are not going to be restarted
+	for chName := range supChildren {
+		if supScheduler.isPending(supChildren, chName) {
+			delete(supChildren, chName)
+			specChildren = removeFinishedChildSpec(specChildren, supChildren, chName)
+		}
+	}
+
+	return specChildren, supChildren
+}
+
+var _ ctrlMsg = drainMsg{}
+
+// Drain stops the DynSupervisor from spawning new children (spawn calls return
+// a SupervisorDrainingError), and it waits for the existing children to finish
+// on their own; children that finish while the supervisor is draining are not
+// restarted. When all the children are done, or when the given context is
+// done, the DynSupervisor gets terminated.
+//
+// Children that finish on their own emit a ProcessDrained event (or a
+// ProcessFailed event if they fail), while children that are still running
+// when the context is done are terminated and emit a ProcessForceTerminated
+// event.
+//
+// This function returns the error of the given context if it is done before
+// the children finish (the termination error of the supervisor, if any, is
+// included in its message); otherwise it returns the termination error of the
+// supervisor.
+func (dyn *DynSupervisor) Drain(ctx context.Context) error {
+	// REMEMBER: WE ARE RUNNING ON THE CLIENT API THREAD
+
+	// if we already registered a terminationErr, return it
+	if dyn.terminated {
+		return fmt.Errorf("supervisor already terminated: %w", dyn.terminationErr)
+	}
+
+	dynState := dyn.sup.spec.dynChildren
+
+	if err := sendCtrlMsg(ctx, dyn.sup.ctrlCh, drainMsg{}); err == nil {
+	drainLoop:
+		for {
+			// we get the removal channel before listing the children, otherwise we
+			// may miss the removal of a child
+			removedCh := dynState.waitRemoval()
+
+			children, err := dyn.listChildren(ctx)
+			if err != nil || len(children) == 0 {
+				break
+			}
+
+			select {
+			case <-removedCh:
+			case <-ctx.Done():
+				break drainLoop
+			}
+		}
+	}
+
+	// when the given context is done, the remaining children are going to be
+	// force-terminated
+	terminateErr := dyn.Terminate()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if terminateErr != nil {
+			return fmt.Errorf("%w (termination error: %v)", ctxErr, terminateErr)
+		}
+		return ctxErr
+	}
+	return terminateErr
+}
@@ -0,0 +1,116 @@
+package s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestDynDrain(t *testing.T) {
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	dyn, err := cap.NewDynSupervisor(
+		ctx,
+		"root",
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+	assert.NoError(t, err)
+
+	child1, completeChild1 := CompleteOnSignalWorker(
+		1, "child1", cap.WithRestart(cap.Permanent),
+	)
+	child2, completeChild2 := CompleteOnSignalWorker(
+		1, "child2", cap.WithRestart(cap.Permanent),
+	)
+
+	_, err = dyn.Spawn(child1)
+	assert.NoError(t, err)
+	_, err = dyn.Spawn(child2)
+	assert.NoError(t, err)
+
+	evIt := evManager.Iterator()
+	evIt.SkipTill(WorkerStarted("root/child2"))
+
+	drainErrCh := make(chan error)
+	go func() {
+		drainErrCh <- dyn.Drain(ctx)
+	}()
+
+	// wait for the drain to start, a draining supervisor rejects new children
+	// before checking for duplicated names
+	var drainingErr *cap.SupervisorDrainingError
+	for {
+		_, err = dyn.Spawn(WaitDoneWorker("child1"))
+		if errors.As(err, &drainingErr) {
+			break
+		}
+		var existsErr *cap.NodeExistsError
+		assert.True(t, errors.As(err, &existsErr))
+		time.Sleep(time.Millisecond)
+	}
+
+	// permanent children that complete are not restarted
+	completeChild1()
+	evIt.SkipTill(WorkerDrained("root/child1"))
+
+	completeChild2()
+
+	assert.NoError(t, <-drainErrCh)
+	evIt.SkipTill(SupervisorTerminated("root"))
+
+	AssertExactMatch(t, evManager.Snapshot(),
+		[]EventP{
+			SupervisorStarted("root"),
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			WorkerDrained("root/child1"),
+			WorkerDrained("root/child2"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestDynDrainDeadline(t *testing.T) {
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	dyn, err := cap.NewDynSupervisor(
+		ctx,
+		"root",
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+	assert.NoError(t, err)
+
+	_, err = dyn.Spawn(WaitDoneWorker("child1"))
+	assert.NoError(t, err)
+
+	evIt := evManager.Iterator()
+	evIt.SkipTill(WorkerStarted("root/child1"))
+
+	drainCtx, drainCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer drainCancel()
+
+	err = dyn.Drain(drainCtx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	evIt.SkipTill(SupervisorTerminated("root"))
+
+	AssertExactMatch(t, evManager.Snapshot(),
+		[]EventP{
+			SupervisorStarted("root"),
+			WorkerStarted("root/child1"),
+			WorkerForceTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
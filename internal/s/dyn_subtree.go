@@ -12,16 +12,28 @@ type Spawner interface {
 	Spawn(Node) (func() error, error)
 }
 
+// ContextSpawner is a Spawner that can give up on a spawn request when the
+// given context is done; the Spawner of a NewDynSubtree worker implements it.
+type ContextSpawner interface {
+	Spawner
+	SpawnContext(context.Context, Node) (func() error, error)
+}
+
 type spawnerClient struct {
 	ctrlChan chan ctrlMsg
+	spec     SupervisorSpec
 }
 
-func newSpawnerClient(ctrlChan chan ctrlMsg) spawnerClient {
-	return spawnerClient{ctrlChan: ctrlChan}
+func newSpawnerClient(ctrlChan chan ctrlMsg, spec SupervisorSpec) spawnerClient {
+	return spawnerClient{ctrlChan: ctrlChan, spec: spec}
 }
 
 func (s spawnerClient) Spawn(node Node) (func() error, error) {
-	return sendSpawnToSupervisor(s.ctrlChan, node)
+	return s.SpawnContext(context.Background(), node)
+}
+
+func (s spawnerClient) SpawnContext(ctx context.Context, node Node) (func() error, error) {
+	return sendSpawnToSupervisor(ctx, s.ctrlChan, s.spec, node)
 }
 
 // NewDynSubtree builds a worker that has receives a Spawner that allows it to
@@ -75,7 +87,9 @@ func NewDynSubtreeWithNotifyStart(
 				// this ctrlChan is going to be used by the subtree
 				ctrlChan := make(chan ctrlMsg)
 
-				spawnerSpec := NewSupervisorSpec("subtree", WithNodes(), spawnerOpts...)
+				spawnerSpec := NewSupervisorSpec(
+					"subtree", WithNodes(), append(spawnerOpts, withDynChildren())...,
+				)
 				spawnerNode := func(parent SupervisorSpec) c.ChildSpec {
 					return parent.subtree(spawnerSpec, ctrlChan, opts...)
 				}
@@ -91,7 +105,7 @@ func NewDynSubtreeWithNotifyStart(
 						func(ctx context.Context, notifyStart NotifyStartFn) error {
 							// we create a value that allows this the spawner to communicate
 							// with the subtree in a safe way.
-							spawner := newSpawnerClient(ctrlChan)
+							spawner := newSpawnerClient(ctrlChan, spawnerSpec)
 							return runFn(ctx, notifyStart, spawner)
 						},
 						opts...,
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/capatazlib/go-capataz/internal/c"
@@ -21,6 +23,7 @@ type ctrlMsg interface {
 		supRuntimeName string,
 		supChildren map[string]c.Child,
 		supNotifyChan chan c.ChildNotification,
+		supScheduler *restartScheduler,
 	) ([]c.ChildSpec, map[string]c.Child)
 }
 
@@ -45,19 +48,64 @@ func (scm startChildMsg) processMsg(
 	supRuntimeName string,
 	supChildren map[string]c.Child,
 	supNotifyChan chan c.ChildNotification,
+	_ *restartScheduler,
 ) ([]c.ChildSpec, map[string]c.Child) {
 	// REMEMBER: WE ARE RUNNING THIS CODE IN THE SUPERVISOR THREAD
 
-	childSpec := scm.node(spec)
+	childSpec := spec.buildNode(scm.node)
 
-	ch, startErr := startChildNode(supCtx, spec, supRuntimeName, supNotifyChan, childSpec)
+	// a draining supervisor does not accept new children
+	if spec.dynChildren.isDraining() {
+		// do not block waiting for a read
+		select {
+		case scm.resultChan <- startChildResult{
+			childName: "",
+			startErr:  &SupervisorDrainingError{supRuntimeName: supRuntimeName},
+		}:
+		default:
+		}
+
+		return specChildren, supChildren
+	}
+
+	// node names must be unique between siblings, otherwise we would not be able
+	// to tell them apart on notifications
+	if _, _, ok := findChildSpec(specChildren, childSpec.GetName()); ok {
+		// do not block waiting for a read
+		select {
+		case scm.resultChan <- startChildResult{
+			childName: "",
+			startErr: &NodeExistsError{
+				nodeName: buildChildRuntimeName(supRuntimeName, childSpec),
+			},
+		}:
+		default:
+		}
+
+		return specChildren, supChildren
+	}
+
+	if spec.childrenLimit.isReached(len(supChildren)) {
+		// do not block waiting for a read
+		select {
+		case scm.resultChan <- startChildResult{
+			childName: "",
+			startErr: &MaxChildrenReachedError{
+				supRuntimeName: supRuntimeName,
+				maxChildren:    spec.childrenLimit.maxChildren,
+			},
+		}:
+		default:
+		}
+
+		return specChildren, supChildren
+	}
+
+	ch, startErr := startChildNode(supCtx, spec, supRuntimeName, supNotifyChan, childSpec, 0)
 	if startErr != nil {
-		// When we fail, we send an error to the supNotifyChan and return the error,
-		// this doesn't have any detrimental consequence in static supervisors,
-		// but on dynamic supervisors, it means the monitor loop will get bothered
-		// with an error that it should not really handle. We are going to read it
-		// out and return after that.
-		_ = <-supNotifyChan
+		// NOTE: children that fail to start do not report their termination to
+		// the supNotifyChan, the monitor loop never knew about them.
+		//
 		// do not block waiting for a read
 		select {
 		case scm.resultChan <- startChildResult{
@@ -72,10 +120,12 @@ func (scm startChildMsg) processMsg(
 	}
 
 	// We store the child to the spec list because we need to terminate them
-	// when the supervisor is terminated in the correct order. This won't have
-	// unintended side-effects because a DynSupervisor once terminated, cannot
-	// be started again.
-	specChildren = append(specChildren, childSpec)
+	// when the supervisor is terminated in the correct order, and because
+	// static supervisors must include the child on their restarts. We copy the
+	// list given it may be shared with other references of the children specs.
+	newSpecChildren := make([]c.ChildSpec, 0, len(specChildren)+1)
+	newSpecChildren = append(newSpecChildren, specChildren...)
+	specChildren = append(newSpecChildren, childSpec)
 	supChildren[ch.GetName()] = ch
 
 	select {
@@ -108,6 +158,7 @@ func (tcm terminateChildMsg) processMsg(
 	supRuntimeName string,
 	supChildren map[string]c.Child,
 	supNotifyChan chan c.ChildNotification,
+	_ *restartScheduler,
 ) ([]c.ChildSpec, map[string]c.Child) {
 	// REMEMBER: WE ARE RUNNING THIS CODE IN THE SUPERVISOR THREAD
 
@@ -125,7 +176,7 @@ func (tcm terminateChildMsg) processMsg(
 
 	// we call our basic terminateChildNode function that is found in the
 	// monitor.go file
-	terminateErr := terminateChildNode(evNotifier, ch)
+	terminateErr := terminateChildNode(evNotifier, spec.inbox, ch)
 
 	// do not block waiting for a read
 	select {
@@ -152,6 +203,12 @@ type DynSupervisor struct {
 	sup            Supervisor
 	terminated     bool
 	terminationErr error
+
+	// template builds the node used to spawn every child of a DynSupervisor
+	// created with NewSimpleOneForOneSupervisor (or with
+	// NewDynSupervisorFromTemplate), it receives the argument of the spawn call
+	template   func(arg interface{}) Node
+	spawnCount *uint64
 }
 
 // handleCtrlMsg is used in the supervisor monitor loop to operator over public
@@ -164,6 +221,7 @@ func handleCtrlMsg(
 	supRuntimeName string,
 	supChildren map[string]c.Child,
 	supNotifyChan chan c.ChildNotification,
+	supScheduler *restartScheduler,
 	msg ctrlMsg,
 ) ([]c.ChildSpec, map[string]c.Child) {
 	return msg.processMsg(
@@ -174,6 +232,7 @@ func handleCtrlMsg(
 		supRuntimeName,
 		supChildren,
 		supNotifyChan,
+		supScheduler,
 	)
 }
 
@@ -225,7 +284,39 @@ func buildTerminateNodeCallback(ctrlChan chan ctrlMsg, nodeName string) func() e
 	}
 }
 
-func sendSpawnToSupervisor(ctrlChan chan ctrlMsg, node Node) (func() error, error) {
+func sendSpawnToSupervisor(
+	ctx context.Context,
+	ctrlChan chan ctrlMsg,
+	spec SupervisorSpec,
+	node Node,
+) (func() error, error) {
+	for {
+		// we get the slot channel before sending the spawn request, otherwise we
+		// may miss the removal of a child
+		var slotCh <-chan struct{}
+		limit := spec.childrenLimit
+		if limit != nil && limit.policy == WaitForSlot && spec.dynChildren != nil {
+			slotCh = spec.dynChildren.waitRemoval()
+		}
+
+		cancelFn, err := sendStartChildMsg(ctrlChan, node)
+
+		var maxChildrenErr *MaxChildrenReachedError
+		if slotCh == nil || !errors.As(err, &maxChildrenErr) {
+			return cancelFn, err
+		}
+
+		// the supervisor is full, wait until a child is removed and try again
+		select {
+		case <-slotCh:
+		case <-ctx.Done():
+			maxChildrenErr.waitErr = ctx.Err()
+			return nil, maxChildrenErr
+		}
+	}
+}
+
+func sendStartChildMsg(ctrlChan chan ctrlMsg, node Node) (func() error, error) {
 	// we initialize the resultChan with a buffer of 1, we may store the result
 	// before the client is ready to read it.
 	resultChan := make(chan startChildResult, 1)
@@ -234,13 +325,13 @@ func sendSpawnToSupervisor(ctrlChan chan ctrlMsg, node Node) (func() error, erro
 		resultChan: resultChan,
 	}
 
-	select {
-	case ctrlChan <- msg:
-	case _ = <-time.After(1 * time.Second):
-		// This scenario can happen when the supervisor is being terminated and the
-		// non-blocking sup.GetCrashError happened just before that (race
-		// condition).
-		return nil, errors.New("could not talk to supervisor")
+	// This timeout may be reached when the supervisor is being terminated and the
+	// non-blocking sup.GetCrashError happened just before that (race condition).
+	sendCtx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	if err := sendCtrlMsg(sendCtx, ctrlChan, msg); err != nil {
+		return nil, err
 	}
 
 	select {
@@ -264,7 +355,19 @@ func sendSpawnToSupervisor(ctrlChan chan ctrlMsg, node Node) (func() error, erro
 // either returns a cancel/shutdown callback or an error in the scenario the
 // start of this worker failed. This function blocks until the worker is
 // started.
+//
+// When the supervisor was created with WithMaxChildren and the WaitForSlot
+// policy, this function blocks until the supervisor is able to spawn the
+// worker; use SpawnContext to give up after a deadline.
 func (dyn *DynSupervisor) Spawn(nodeFn Node) (func() error, error) {
+	return dyn.SpawnContext(context.Background(), nodeFn)
+}
+
+// SpawnContext accomplishes the same goal as Spawn; when the supervisor was
+// created with WithMaxChildren and the WaitForSlot policy, it returns a
+// MaxChildrenReachedError if the given context is done before the supervisor
+// is able to spawn the worker.
+func (dyn *DynSupervisor) SpawnContext(ctx context.Context, nodeFn Node) (func() error, error) {
 	// REMEMBER: WE ARE RUNNING ON THE CLIENT API THREAD
 
 	// if we already registered a terminationErr, return it
@@ -279,7 +382,108 @@ func (dyn *DynSupervisor) Spawn(nodeFn Node) (func() error, error) {
 		return nil, fmt.Errorf("supervisor already terminated: %w", terminationErr)
 	}
 
-	return sendSpawnToSupervisor(dyn.sup.ctrlCh, nodeFn)
+	if dyn.template != nil {
+		return nil, errors.New("supervisor spawns children from a template, use SpawnFromTemplate")
+	}
+
+	return sendSpawnToSupervisor(ctx, dyn.sup.ctrlCh, dyn.sup.spec, nodeFn)
+}
+
+// SpawnFromTemplate creates a new child from the template node of a
+// DynSupervisor created with NewSimpleOneForOneSupervisor. The child gets a
+// unique name composed of the template name and a sequence number (e.g.
+// conn-000123). It returns the runtime name of the child, and either a
+// cancel/shutdown callback or an error in the scenario the start of the child
+// failed. This function blocks until the child is started.
+func (dyn *DynSupervisor) SpawnFromTemplate() (string, func() error, error) {
+	return dyn.spawnFromTemplate(context.Background(), nil)
+}
+
+// spawnFromTemplate creates a new child from the template of a DynSupervisor
+// created with NewSimpleOneForOneSupervisor (or with
+// NewDynSupervisorFromTemplate); the given argument is passed to the template.
+func (dyn *DynSupervisor) spawnFromTemplate(
+	ctx context.Context,
+	arg interface{},
+) (string, func() error, error) {
+	if dyn.template == nil {
+		return "", nil, errors.New("supervisor was not created with a template")
+	}
+	return dyn.spawnUnique(ctx, dyn.template(arg))
+}
+
+// spawnUnique creates a new worker routine from the given node specification,
+// renaming it with a unique name composed of the node name and a sequence
+// number. It returns the runtime name of the new child.
+func (dyn *DynSupervisor) spawnUnique(
+	ctx context.Context,
+	template Node,
+) (string, func() error, error) {
+	// REMEMBER: WE ARE RUNNING ON THE CLIENT API THREAD
+
+	// if we already registered a terminationErr, return it
+	if dyn.terminated {
+		return "", nil, fmt.Errorf("supervisor already terminated: %w", dyn.terminationErr)
+	}
+
+	// if the underlying supervisor is kaput, return the error
+	if terminated, terminationErr := dyn.sup.GetCrashError(false); terminated {
+		dyn.terminated = true
+		dyn.terminationErr = terminationErr
+		return "", nil, fmt.Errorf("supervisor already terminated: %w", terminationErr)
+	}
+
+	spawnIx := atomic.AddUint64(dyn.spawnCount, 1)
+	var nodeName string
+
+	node := func(supSpec SupervisorSpec) c.ChildSpec {
+		chSpec := template(supSpec)
+		chSpec.Name = fmt.Sprintf("%s-%06d", chSpec.GetName(), spawnIx)
+		nodeName = chSpec.GetName()
+		return chSpec
+	}
+
+	cancelFn, err := sendSpawnToSupervisor(ctx, dyn.sup.ctrlCh, dyn.sup.spec, node)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return strings.Join([]string{dyn.sup.runtimeName, nodeName}, NodeSepToken), cancelFn, nil
+}
+
+// listChildren returns the runtime names of the children of the DynSupervisor
+// that are running (or waiting for a restart), following the start order of
+// the supervisor.
+func (dyn DynSupervisor) listChildren(ctx context.Context) ([]string, error) {
+	// REMEMBER: WE ARE RUNNING ON THE CLIENT API THREAD
+
+	if terminated, _ := dyn.sup.GetCrashError(false); terminated {
+		return nil, errors.New("supervisor already terminated")
+	}
+
+	// we initialize the resultChan with a buffer of 1, we may store the result
+	// before the client is ready to read it.
+	resultChan := make(chan []NodeSnapshot, 1)
+
+	err := sendCtrlMsg(ctx, dyn.sup.ctrlCh, inspectMsg{resultChan: resultChan})
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []NodeSnapshot
+	select {
+	case snapshots = <-resultChan:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("could not get a snapshot from supervisor: %w", ctx.Err())
+	}
+
+	names := make([]string, 0, len(snapshots))
+	for _, ns := range snapshots {
+		if ns.GetState() != NodeStopped {
+			names = append(names, ns.GetRuntimeName())
+		}
+	}
+	return names, nil
 }
 
 // Terminate is a synchronous procedure that halts the execution of the whole
@@ -323,11 +527,72 @@ func (dyn DynSupervisor) GetName() string {
 // * In case of a hard crash and following restart, it will start with an empty
 //   list of children
 //
+// Restarts of spawned children
+//
+// Spawned children are supervised with the restart strategy (WithStrategy) and
+// the restart tolerance (WithRestartTolerance) of the DynSupervisor:
+//
+// * Permanent children are always restarted, Transient children are restarted
+//   when they fail, and Temporary children are never restarted
+//
+// * The OneForAll and RestForOne strategies consider the children in the
+//   order they were spawned
+//
+// * When the restart tolerance is surpassed, the DynSupervisor fails the same
+//   way a static Supervisor does; the Wait method returns the restart error and
+//   the Spawn method returns an error
+//
+// * Children that are not going to be restarted (e.g. a Temporary child that
+//   finished) are removed from the DynSupervisor, their names may be used again
+//   on following Spawn calls
+//
 func NewDynSupervisor(ctx context.Context, name string, opts ...Opt) (DynSupervisor, error) {
+	return newDynSupervisor(ctx, name, opts)
+}
+
+// NewSimpleOneForOneSupervisor creates a DynSupervisor in which every child is
+// spawned from the given template node with the SpawnFromTemplate method. This
+// is the equivalent of Erlang's simple_one_for_one supervisor; the children of
+// this DynSupervisor are independent from each other, so they always get
+// restarted with the OneForOne strategy.
+//
+// Calls to the Spawn method of the returned DynSupervisor return an error.
+func NewSimpleOneForOneSupervisor(
+	ctx context.Context,
+	name string,
+	template Node,
+	opts ...Opt,
+) (DynSupervisor, error) {
+	return newSimpleOneForOneSupervisor(
+		ctx,
+		name,
+		func(interface{}) Node { return template },
+		opts,
+	)
+}
+
+// newSimpleOneForOneSupervisor creates a DynSupervisor in which every child is
+// spawned from the node returned by the given template function.
+func newSimpleOneForOneSupervisor(
+	ctx context.Context,
+	name string,
+	template func(arg interface{}) Node,
+	opts []Opt,
+) (DynSupervisor, error) {
+	dyn, err := newDynSupervisor(ctx, name, append(opts[:len(opts):len(opts)], WithStrategy(OneForOne)))
+	if err != nil {
+		return DynSupervisor{}, err
+	}
+	dyn.template = template
+	return dyn, nil
+}
+
+func newDynSupervisor(ctx context.Context, name string, opts []Opt) (DynSupervisor, error) {
+	opts = append(opts[:len(opts):len(opts)], withDynChildren())
 	spec := NewSupervisorSpec(name, WithNodes(), opts...)
 	sup, err := spec.Start(ctx)
 	if err != nil {
 		return DynSupervisor{}, err
 	}
-	return DynSupervisor{sup: sup}, nil
+	return DynSupervisor{sup: sup, spawnCount: new(uint64)}, nil
 }
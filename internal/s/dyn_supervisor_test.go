@@ -7,6 +7,7 @@ package s_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -223,6 +224,7 @@ func TestDynTerminateFailedChild(t *testing.T) {
 			WorkerFailed("root/branch1/child2"),
 			// ^^^ child2 never stops and fails with a timeout caused by the
 			// NeverTerminateWorker specification
+			WorkerAbandoned("root/branch1/child2"),
 			SupervisorFailed("root/branch1"),
 			// ^^^ The branch1 supervisor fails because of child2 timeout
 			WorkerTerminated("root/branch0/child1"),
@@ -389,3 +391,189 @@ func TestDynCancelAlreadyTerminatedSupervisor(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, "could not talk to supervisor: send on closed channel", err.Error())
 }
+
+func TestDynPermanentChildRestart(t *testing.T) {
+	failingNode, failWorker := FailOnSignalWorker(
+		2, "failing", cap.WithRestart(cap.Permanent),
+	)
+
+	events, errs := ObserveDynSupervisor(
+		context.TODO(),
+		"root",
+		[]cap.Node{failingNode},
+		[]cap.Opt{
+			cap.WithRestartTolerance(10, 10*time.Second),
+		},
+		func(sup cap.DynSupervisor, em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(WorkerStarted("root/failing"))
+
+			failWorker(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/failing"))
+
+			failWorker(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/failing"))
+		},
+	)
+
+	assert.Empty(t, errs)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			SupervisorStarted("root"),
+			WorkerStarted("root/failing"),
+			WorkerFailed("root/failing"),
+			WorkerStarted("root/failing"),
+			WorkerFailed("root/failing"),
+			WorkerStarted("root/failing"),
+			WorkerTerminated("root/failing"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestDynRestartToleranceReached(t *testing.T) {
+	failingNode, failWorker := FailOnSignalWorker(
+		2, "failing", cap.WithRestart(cap.Permanent),
+	)
+
+	events, errs := ObserveDynSupervisor(
+		context.TODO(),
+		"root",
+		[]cap.Node{WaitDoneWorker("child1"), failingNode},
+		[]cap.Opt{
+			cap.WithRestartTolerance(1, 10*time.Second),
+		},
+		func(sup cap.DynSupervisor, em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(WorkerStarted("root/failing"))
+
+			failWorker(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/failing"))
+
+			failWorker(false /* done */)
+			evIt.SkipTill(WorkerTerminated("root/child1"))
+
+			err := sup.Wait()
+			var restartErr *cap.SupervisorRestartError
+			assert.True(t, errors.As(err, &restartErr))
+
+			_, err = sup.Spawn(WaitDoneWorker("child2"))
+			assert.Error(t, err)
+		},
+	)
+
+	assert.NotEmpty(t, errs)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			SupervisorStarted("root"),
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/failing"),
+			WorkerFailed("root/failing"),
+			WorkerStarted("root/failing"),
+			WorkerFailed("root/failing"),
+			WorkerTerminated("root/child1"),
+			SupervisorFailed("root"),
+		},
+	)
+}
+
+func TestDynOneForAllSkipsFinishedChildren(t *testing.T) {
+	completingNode, completeWorker := CompleteOnSignalWorker(
+		1, "completing", cap.WithRestart(cap.Temporary),
+	)
+	failingNode, failWorker := FailOnSignalWorker(
+		1, "failing", cap.WithRestart(cap.Permanent),
+	)
+
+	events, errs := ObserveDynSupervisor(
+		context.TODO(),
+		"root",
+		[]cap.Node{completingNode, failingNode},
+		[]cap.Opt{
+			cap.WithStrategy(cap.OneForAll),
+			cap.WithRestartTolerance(10, 10*time.Second),
+		},
+		func(sup cap.DynSupervisor, em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(WorkerStarted("root/failing"))
+
+			// the temporary child is removed from the supervisor once it is done
+			completeWorker()
+			evIt.SkipTill(WorkerCompleted("root/completing"))
+
+			// the finished child is not restarted with its siblings
+			failWorker(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/failing"))
+
+			// the name of the finished child can be used again
+			_, err := sup.Spawn(WaitDoneWorker("completing"))
+			assert.NoError(t, err)
+		},
+	)
+
+	assert.Empty(t, errs)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			SupervisorStarted("root"),
+			WorkerStarted("root/completing"),
+			WorkerStarted("root/failing"),
+			WorkerCompleted("root/completing"),
+			WorkerFailed("root/failing"),
+			WorkerStarted("root/failing"),
+			WorkerStarted("root/completing"),
+			WorkerTerminated("root/completing"),
+			WorkerTerminated("root/failing"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestSimpleOneForOneSupervisor(t *testing.T) {
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	dyn, err := cap.NewSimpleOneForOneSupervisor(
+		ctx,
+		"root",
+		WaitDoneWorker("conn"),
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+	assert.NoError(t, err)
+
+	name1, cancelConn1, err := dyn.SpawnFromTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, "root/conn-000001", name1)
+
+	name2, _, err := dyn.SpawnFromTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, "root/conn-000002", name2)
+
+	// every child must come from the template
+	_, err = dyn.Spawn(WaitDoneWorker("other"))
+	assert.Error(t, err)
+
+	assert.NoError(t, cancelConn1())
+	assert.NoError(t, dyn.Terminate())
+
+	evIt := evManager.Iterator()
+	evIt.SkipTill(SupervisorTerminated("root"))
+
+	AssertExactMatch(t, evManager.Snapshot(),
+		[]EventP{
+			SupervisorStarted("root"),
+			WorkerStarted("root/conn-000001"),
+			WorkerStarted("root/conn-000002"),
+			WorkerTerminated("root/conn-000001"),
+			WorkerTerminated("root/conn-000002"),
+			SupervisorTerminated("root"),
+		},
+	)
+
+	_, _, err = dyn.SpawnFromTemplate()
+	assert.Error(t, err)
+}
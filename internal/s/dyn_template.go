@@ -0,0 +1,114 @@
+package s
+
+import (
+	"context"
+)
+
+// TemplateDynSupervisor is a DynSupervisor in which every child is spawned from
+// a template function that receives an argument of type T (e.g. a network
+// connection). It is a typed version of the DynSupervisor created with
+// NewSimpleOneForOneSupervisor, the equivalent of Erlang's simple_one_for_one
+// supervisor.
+type TemplateDynSupervisor[T any] struct {
+	dyn *DynSupervisor
+}
+
+// NewDynSupervisorFromTemplate creates a TemplateDynSupervisor, which spawns
+// children from the given template function with the SpawnWith method. It
+// receives a context, the supervisor name (for tracing purposes) and the same
+// options a DynSupervisor receives. Given the children of this supervisor are
+// independent from each other, they always get restarted with the OneForOne
+// strategy.
+func NewDynSupervisorFromTemplate[T any](
+	ctx context.Context,
+	name string,
+	template func(arg T) Node,
+	opts ...Opt,
+) (*TemplateDynSupervisor[T], error) {
+	dyn, err := newSimpleOneForOneSupervisor(
+		ctx,
+		name,
+		func(arg interface{}) Node {
+			// the argument is always a T given by SpawnWith, the assertion only
+			// fails for nil values of interface types
+			typedArg, _ := arg.(T)
+			return template(typedArg)
+		},
+		opts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateDynSupervisor[T]{dyn: &dyn}, nil
+}
+
+// SpawnWith creates a new child from the template function with the given
+// argument. The child gets a unique name composed of the name of the node
+// returned by the template and a sequence number (e.g. conn-000123). It returns
+// the runtime name of the child, and either a cancel/shutdown callback or an
+// error in the scenario the start of the child failed. This function blocks
+// until the child is started.
+func (tdyn *TemplateDynSupervisor[T]) SpawnWith(arg T) (string, func() error, error) {
+	return tdyn.SpawnWithContext(context.Background(), arg)
+}
+
+// SpawnWithContext accomplishes the same goal as SpawnWith; when the supervisor
+// was created with WithMaxChildren and the WaitForSlot policy, it returns a
+// MaxChildrenReachedError if the given context is done before the supervisor
+// is able to spawn the child.
+func (tdyn *TemplateDynSupervisor[T]) SpawnWithContext(
+	ctx context.Context,
+	arg T,
+) (string, func() error, error) {
+	return tdyn.dyn.spawnFromTemplate(ctx, arg)
+}
+
+// Count returns the number of children that are running (or waiting for a
+// restart). It returns an error when the supervisor is terminated, or when the
+// given context is done before the supervisor replies.
+func (tdyn *TemplateDynSupervisor[T]) Count(ctx context.Context) (int, error) {
+	names, err := tdyn.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(names), nil
+}
+
+// List returns the runtime names of the children that are running (or waiting
+// for a restart), following the start order of the supervisor. It returns an
+// error when the supervisor is terminated, or when the given context is done
+// before the supervisor replies.
+func (tdyn *TemplateDynSupervisor[T]) List(ctx context.Context) ([]string, error) {
+	return tdyn.dyn.listChildren(ctx)
+}
+
+// Inspect returns a snapshot of the runtime state of all the nodes in the
+// supervision tree. This function blocks until every supervisor in the tree
+// reports its children, or until the given context is done.
+func (tdyn *TemplateDynSupervisor[T]) Inspect(ctx context.Context) (TreeSnapshot, error) {
+	return tdyn.dyn.Inspect(ctx)
+}
+
+// Terminate is a synchronous procedure that halts the execution of the whole
+// supervision tree.
+func (tdyn *TemplateDynSupervisor[T]) Terminate() error {
+	return tdyn.dyn.Terminate()
+}
+
+// Wait blocks the execution of the current goroutine until the Supervisor
+// finishes it execution.
+func (tdyn *TemplateDynSupervisor[T]) Wait() error {
+	return tdyn.dyn.Wait()
+}
+
+// GetName returns the name of the Spec used to start this Supervisor
+func (tdyn *TemplateDynSupervisor[T]) GetName() string {
+	return tdyn.dyn.GetName()
+}
+
+// Drain stops the supervisor from spawning new children, and it waits for the
+// existing children to finish on their own before terminating the supervisor;
+// check DynSupervisor.Drain for more details.
+func (tdyn *TemplateDynSupervisor[T]) Drain(ctx context.Context) error {
+	return tdyn.dyn.Drain(ctx)
+}
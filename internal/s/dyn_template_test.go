@@ -0,0 +1,141 @@
+package s_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestDynSupervisorFromTemplate(t *testing.T) {
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	results := make(chan string, 3)
+
+	tdyn, err := cap.NewDynSupervisorFromTemplate(
+		ctx,
+		"root",
+		func(arg int) cap.Node {
+			return cap.NewWorker(
+				"conn",
+				func(ctx context.Context) error {
+					results <- fmt.Sprintf("conn %d", arg)
+					<-ctx.Done()
+					return nil
+				},
+			)
+		},
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "root", tdyn.GetName())
+	count, err := tdyn.Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	name1, cancelConn1, err := tdyn.SpawnWith(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "root/conn-000001", name1)
+	assert.Equal(t, "conn 1", <-results)
+
+	name2, _, err := tdyn.SpawnWith(2)
+	assert.NoError(t, err)
+	assert.Equal(t, "root/conn-000002", name2)
+	assert.Equal(t, "conn 2", <-results)
+
+	count, err = tdyn.Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	names, err := tdyn.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"root/conn-000001", "root/conn-000002"}, names)
+
+	assert.NoError(t, cancelConn1())
+	names, err = tdyn.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"root/conn-000002"}, names)
+
+	// sequence numbers are never re-used
+	name3, _, err := tdyn.SpawnWith(3)
+	assert.NoError(t, err)
+	assert.Equal(t, "root/conn-000003", name3)
+	assert.Equal(t, "conn 3", <-results)
+
+	assert.NoError(t, tdyn.Terminate())
+	evIt := evManager.Iterator()
+	evIt.SkipTill(SupervisorTerminated("root"))
+
+	// a terminated supervisor cannot report its children
+	_, err = tdyn.Count(ctx)
+	assert.Error(t, err)
+	_, err = tdyn.List(ctx)
+	assert.Error(t, err)
+
+	_, _, err = tdyn.SpawnWith(4)
+	assert.Error(t, err)
+
+	AssertExactMatch(t, evManager.Snapshot(),
+		[]EventP{
+			SupervisorStarted("root"),
+			WorkerStarted("root/conn-000001"),
+			WorkerStarted("root/conn-000002"),
+			WorkerTerminated("root/conn-000001"),
+			WorkerStarted("root/conn-000003"),
+			WorkerTerminated("root/conn-000003"),
+			WorkerTerminated("root/conn-000002"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestDynSupervisorFromTemplateFinishedChildren(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	evManager := NewEventManager()
+	evManager.StartCollector(ctx)
+
+	tdyn, err := cap.NewDynSupervisorFromTemplate(
+		ctx,
+		"root",
+		func(done chan struct{}) cap.Node {
+			return cap.NewWorker(
+				"conn",
+				func(ctx context.Context) error {
+					select {
+					case <-done:
+					case <-ctx.Done():
+					}
+					return nil
+				},
+				cap.WithRestart(cap.Temporary),
+			)
+		},
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+	assert.NoError(t, err)
+
+	done1 := make(chan struct{})
+	_, _, err = tdyn.SpawnWith(done1)
+	assert.NoError(t, err)
+	_, _, err = tdyn.SpawnWith(make(chan struct{}))
+	assert.NoError(t, err)
+
+	// a finished child is not listed anymore
+	close(done1)
+	evIt := evManager.Iterator()
+	evIt.SkipTill(WorkerCompleted("root/conn-000001"))
+
+	names, err := tdyn.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"root/conn-000002"}, names)
+	assert.NoError(t, tdyn.Terminate())
+}
@@ -218,12 +218,23 @@ func (err *SupervisorTerminationError) explainLines() []string {
 type SupervisorBuildError struct {
 	supRuntimeName string
 	buildNodesErr  error
+	// panicErr is set when the build nodes function panicked
+	panicErr *c.PanicError
 }
 
 func (err *SupervisorBuildError) Error() string {
 	return "supervisor build nodes function failed"
 }
 
+// Unwrap returns the error reported by the build nodes function, panics are
+// reported with a PanicError
+func (err *SupervisorBuildError) Unwrap() error {
+	if err.panicErr != nil {
+		return err.panicErr
+	}
+	return err.buildNodesErr
+}
+
 // KVs returns a metadata map for structured logging
 func (err *SupervisorBuildError) KVs() map[string]interface{} {
 	acc := make(map[string]interface{})
@@ -268,6 +279,11 @@ func (err *SupervisorStartError) Error() string {
 	return "supervisor node failed to start"
 }
 
+// Unwrap returns the error reported by the node that failed to start
+func (err *SupervisorStartError) Unwrap() error {
+	return err.nodeErr
+}
+
 // KVs returns a metadata map for structured logging
 func (err *SupervisorStartError) KVs() map[string]interface{} {
 	acc := make(map[string]interface{})
@@ -349,20 +365,47 @@ func (err *SupervisorStartError) explainLines() []string {
 	return outputLines
 }
 
-// SupervisorRestartError wraps an error tolerance surpassed error from a child
-// node, enhancing it with supervisor information and possible termination errors
-// on other siblings
+// restartGiveUpError is an error that makes a supervisor give up on restarting
+// its children (RestartToleranceReached or PanicEscalatedError)
+type restartGiveUpError interface {
+	error
+	KVs() map[string]interface{}
+	explainLines() []string
+}
+
+// SupervisorRestartError wraps an error tolerance surpassed error (or an
+// escalated panic) from a child node, enhancing it with supervisor information
+// and possible termination errors on other siblings
 type SupervisorRestartError struct {
 	supRuntimeName string
-	nodeErr        *RestartToleranceReached
+	nodeErr        restartGiveUpError
 	terminationErr *SupervisorTerminationError
 }
 
+// isPanicEscalated indicates if the supervisor gave up because of an escalated
+// panic
+func (err *SupervisorRestartError) isPanicEscalated() bool {
+	_, ok := err.nodeErr.(*PanicEscalatedError)
+	return ok
+}
+
 // Error returns an error message
 func (err *SupervisorRestartError) Error() string {
+	if err.isPanicEscalated() {
+		return "supervisor crashed due to an escalated panic"
+	}
 	return "supervisor crashed due to restart tolerance surpassed"
 }
 
+// Unwrap returns the error that made the supervisor give up on restarting its
+// children
+func (err *SupervisorRestartError) Unwrap() error {
+	if err.nodeErr == nil {
+		return nil
+	}
+	return err.nodeErr
+}
+
 // KVs returns a metadata map for structured logging
 func (err *SupervisorRestartError) KVs() map[string]interface{} {
 	acc := make(map[string]interface{})
@@ -388,11 +431,16 @@ func (err *SupervisorRestartError) KVs() map[string]interface{} {
 func (err *SupervisorRestartError) explainLines() []string {
 	var outputLines []string
 
+	reason := "restart tolerance surpassed"
+	if err.isPanicEscalated() {
+		reason = "an escalated panic"
+	}
 	outputLines = append(
 		outputLines,
 		fmt.Sprintf(
-			"supervisor '%s' crashed due to restart tolerance surpassed.",
+			"supervisor '%s' crashed due to %s.",
 			err.supRuntimeName,
+			reason,
 		),
 	)
 
@@ -496,6 +544,224 @@ func (err *RestartToleranceReached) explainLines() []string {
 	return outputLines
 }
 
+// PanicEscalatedError is an error that gets reported when a child with the
+// EscalateOnPanic policy panicked, and its supervisor gave up on restarting its
+// children regardless of the restart tolerance.
+type PanicEscalatedError struct {
+	nodeName string
+	panicErr error
+}
+
+// newPanicEscalatedError creates a PanicEscalatedError for the given child
+func newPanicEscalatedError(sourceCh c.Child, panicErr error) *PanicEscalatedError {
+	return &PanicEscalatedError{
+		nodeName: sourceCh.GetRuntimeName(),
+		panicErr: panicErr,
+	}
+}
+
+// Error returns an error message
+func (err *PanicEscalatedError) Error() string {
+	return "node panic escalated"
+}
+
+// GetNodeName returns the runtime name of the node that panicked
+func (err *PanicEscalatedError) GetNodeName() string {
+	return err.nodeName
+}
+
+// Unwrap returns the PanicError of the node that panicked
+func (err *PanicEscalatedError) Unwrap() error {
+	return err.panicErr
+}
+
+// KVs returns a data bag map that may be used in structured logging
+func (err *PanicEscalatedError) KVs() map[string]interface{} {
+	kvs := make(map[string]interface{})
+	kvs["node.name"] = err.nodeName
+	kvs["node.error.panic.msg"] = err.panicErr.Error()
+	return kvs
+}
+
+// explainLines returns a human-friendly message of the error represented as a slice
+// of lines
+func (err *PanicEscalatedError) explainLines() []string {
+	outputLines := []string{
+		fmt.Sprintf(
+			"node '%s' panicked, the panic was escalated to its supervisor.",
+			err.nodeName,
+		),
+	}
+	return append(outputLines, indentExplain(1, errToExplain(err.panicErr))...)
+}
+
+// NodeNotFoundError is an error that gets reported when a supervisor API call
+// receives a runtime name that does not match any node of the supervision
+// tree.
+type NodeNotFoundError struct {
+	nodeName string
+}
+
+// Error returns an error message
+func (err *NodeNotFoundError) Error() string {
+	return "node not found"
+}
+
+// KVs returns a data bag map that may be used in structured logging
+func (err *NodeNotFoundError) KVs() map[string]interface{} {
+	kvs := make(map[string]interface{})
+	kvs["node.name"] = err.nodeName
+	return kvs
+}
+
+// explainLines returns a human-friendly message of the error represented as a slice
+// of lines
+func (err *NodeNotFoundError) explainLines() []string {
+	return []string{
+		fmt.Sprintf("node '%s' is not part of the supervision tree", err.nodeName),
+	}
+}
+
+// NodeRunningError is an error that gets reported when a supervisor API call
+// requires a node to be terminated, and the node is running.
+type NodeRunningError struct {
+	nodeName string
+}
+
+// Error returns an error message
+func (err *NodeRunningError) Error() string {
+	return "node is running"
+}
+
+// KVs returns a data bag map that may be used in structured logging
+func (err *NodeRunningError) KVs() map[string]interface{} {
+	kvs := make(map[string]interface{})
+	kvs["node.name"] = err.nodeName
+	return kvs
+}
+
+// explainLines returns a human-friendly message of the error represented as a slice
+// of lines
+func (err *NodeRunningError) explainLines() []string {
+	return []string{
+		fmt.Sprintf("node '%s' is running, it must be terminated first", err.nodeName),
+	}
+}
+
+// NodeExistsError is an error that gets reported when a supervisor API call
+// tries to add a node with a name that is already used by a sibling node.
+type NodeExistsError struct {
+	nodeName string
+}
+
+// Error returns an error message
+func (err *NodeExistsError) Error() string {
+	return "node already exists"
+}
+
+// KVs returns a data bag map that may be used in structured logging
+func (err *NodeExistsError) KVs() map[string]interface{} {
+	kvs := make(map[string]interface{})
+	kvs["node.name"] = err.nodeName
+	return kvs
+}
+
+// explainLines returns a human-friendly message of the error represented as a slice
+// of lines
+func (err *NodeExistsError) explainLines() []string {
+	return []string{
+		fmt.Sprintf("node '%s' is already part of the supervision tree", err.nodeName),
+	}
+}
+
+// ErrMaxChildrenReached is the value that matches (using errors.Is) a
+// MaxChildrenReachedError
+var ErrMaxChildrenReached = errors.New("max children reached")
+
+// MaxChildrenReachedError is an error that gets reported when a supervisor is
+// asked to spawn a child and it already supervises the maximum number of
+// children specified with WithMaxChildren.
+type MaxChildrenReachedError struct {
+	supRuntimeName string
+	maxChildren    int
+	waitErr        error
+}
+
+// Error returns an error message
+func (err *MaxChildrenReachedError) Error() string {
+	return ErrMaxChildrenReached.Error()
+}
+
+// Is returns true when the given error is ErrMaxChildrenReached
+func (err *MaxChildrenReachedError) Is(target error) bool {
+	return target == ErrMaxChildrenReached
+}
+
+// Unwrap returns the error that stopped the wait for a free slot (e.g.
+// context.DeadlineExceeded), if any
+func (err *MaxChildrenReachedError) Unwrap() error {
+	return err.waitErr
+}
+
+// KVs returns a data bag map that may be used in structured logging
+func (err *MaxChildrenReachedError) KVs() map[string]interface{} {
+	kvs := make(map[string]interface{})
+	kvs["supervisor.name"] = err.supRuntimeName
+	kvs["supervisor.max_children"] = err.maxChildren
+	if err.waitErr != nil {
+		kvs["supervisor.wait.error"] = err.waitErr.Error()
+	}
+	return kvs
+}
+
+// explainLines returns a human-friendly message of the error represented as a slice
+// of lines
+func (err *MaxChildrenReachedError) explainLines() []string {
+	outputLines := []string{
+		fmt.Sprintf(
+			"supervisor '%s' cannot spawn more than %d children",
+			err.supRuntimeName,
+			err.maxChildren,
+		),
+	}
+	if err.waitErr != nil {
+		outputLines = append(
+			outputLines,
+			fmt.Sprintf("gave up waiting for a free slot: %v", err.waitErr),
+		)
+	}
+	return outputLines
+}
+
+// SupervisorDrainingError is an error that gets reported when a supervisor is
+// asked to spawn a child after its drain started (see DynSupervisor.Drain).
+type SupervisorDrainingError struct {
+	supRuntimeName string
+}
+
+// Error returns an error message
+func (err *SupervisorDrainingError) Error() string {
+	return "supervisor is draining"
+}
+
+// KVs returns a data bag map that may be used in structured logging
+func (err *SupervisorDrainingError) KVs() map[string]interface{} {
+	kvs := make(map[string]interface{})
+	kvs["supervisor.name"] = err.supRuntimeName
+	return kvs
+}
+
+// explainLines returns a human-friendly message of the error represented as a slice
+// of lines
+func (err *SupervisorDrainingError) explainLines() []string {
+	return []string{
+		fmt.Sprintf(
+			"supervisor '%s' is draining, it does not spawn new children",
+			err.supRuntimeName,
+		),
+	}
+}
+
 ////////////////////
 
 // ExplainError is a utility function that explains capataz errors in a human-friendly
@@ -505,13 +771,27 @@ func ExplainError(err error) string {
 	if errExp, ok := err.(errExplain); ok {
 		return strings.Join(errExp.explainLines(), "\n")
 	}
-	return err.Error()
+	return strings.Join(errMessageLines(err), "\n")
+}
+
+// errMessageLines returns the lines of the message of the given error; when the
+// error comes from a captured panic, the lines of the panic stack trace are
+// included
+func errMessageLines(err error) []string {
+	errLines := strings.Split(err.Error(), "\n")
+	var panicErr *c.PanicError
+	if errors.As(err, &panicErr) && len(panicErr.GetStack()) > 0 {
+		stack := strings.TrimRight(string(panicErr.GetStack()), "\n")
+		errLines = append(errLines, "")
+		errLines = append(errLines, strings.Split(stack, "\n")...)
+	}
+	return errLines
 }
 
 // errToExplain transforms an error message into a human-friendly readbable
 // string
 func errToExplain(err error) []string {
-	errLines := strings.Split(err.Error(), "\n")
+	errLines := errMessageLines(err)
 	for i, l := range errLines {
 		errLines[i] = fmt.Sprintf("> %s", l)
 	}
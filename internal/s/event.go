@@ -26,6 +26,42 @@ const (
 	ProcessFailed
 	// ProcessCompleted is an Event that indicates a process finished without errors
 	ProcessCompleted
+	// ChildRestartToleranceReached is an Event that indicates a process surpassed
+	// its own restart tolerance (specified with the WithTolerance option)
+	ChildRestartToleranceReached
+	// ProcessDrained is an Event that indicates a process finished without
+	// errors while its supervisor was draining (see DynSupervisor.Drain)
+	ProcessDrained
+	// ProcessForceTerminated is an Event that indicates a process was stopped by
+	// a parent supervisor because it did not finish before the drain of the
+	// supervisor was done (see DynSupervisor.Drain)
+	ProcessForceTerminated
+	// ProcessReady is an Event that indicates a process that was started with
+	// the WithReadiness option reported it is ready
+	ProcessReady
+	// ProcessAbandoned is an Event that indicates a process did not stop within
+	// its shutdown timeout, and that its parent supervisor gave up on it; the
+	// process goroutine may still be running
+	ProcessAbandoned
+	// ProcessAbandonedExited is an Event that indicates the goroutine of an
+	// abandoned process finished its execution; this event is only reported
+	// when the parent supervisor of the process is still running
+	ProcessAbandonedExited
+	// SupervisorRestarting is an Event that indicates a supervisor is about to
+	// execute its restart strategy because of a failure or completion of the
+	// source child
+	SupervisorRestarting
+	// RestartToleranceIncremented is an Event that indicates the restart count
+	// of a process restart tolerance was incremented
+	RestartToleranceIncremented
+	// RestartToleranceWindowReset is an Event that indicates the restart
+	// tolerance window of a process expired, and that its restart count started
+	// over
+	RestartToleranceWindowReset
+	// ChildRestartScheduled is an Event that indicates the restart of a process
+	// is going to happen after a delay (specified with the WithRestartBackoff
+	// option)
+	ChildRestartScheduled
 )
 
 // String returns a string representation of the current EventTag
@@ -41,6 +77,26 @@ func (tag EventTag) String() string {
 		return "ProcessFailed"
 	case ProcessCompleted:
 		return "ProcessCompleted"
+	case ChildRestartToleranceReached:
+		return "ChildRestartToleranceReached"
+	case ProcessDrained:
+		return "ProcessDrained"
+	case ProcessForceTerminated:
+		return "ProcessForceTerminated"
+	case ProcessReady:
+		return "ProcessReady"
+	case ProcessAbandoned:
+		return "ProcessAbandoned"
+	case ProcessAbandonedExited:
+		return "ProcessAbandonedExited"
+	case SupervisorRestarting:
+		return "SupervisorRestarting"
+	case RestartToleranceIncremented:
+		return "RestartToleranceIncremented"
+	case RestartToleranceWindowReset:
+		return "RestartToleranceWindowReset"
+	case ChildRestartScheduled:
+		return "ChildRestartScheduled"
 	default:
 		return "<Unknown>"
 	}
@@ -56,6 +112,21 @@ type Event struct {
 	err                error
 	created            time.Time
 	duration           time.Duration
+	awaitsReadiness    bool
+	restart            *restartInfo
+	labels             map[string]string
+}
+
+// restartInfo contains the restart bookkeeping reported on the
+// SupervisorRestarting, RestartToleranceIncremented,
+// RestartToleranceWindowReset and ChildRestartScheduled events
+type restartInfo struct {
+	sourceRuntimeName string
+	restartCount      uint32
+	maxRestartCount   uint32
+	restartWindow     time.Duration
+	restartDelay      time.Duration
+	strategy          Strategy
 }
 
 // GetTag returns the EventTag from an Event
@@ -83,6 +154,94 @@ func (e Event) GetCreated() time.Time {
 	return e.created
 }
 
+// GetDuration returns the time a process took to start on ProcessStarted
+// events, the time it took to stop on ProcessTerminated and
+// ProcessForceTerminated events, and the time an abandoned process kept running
+// on ProcessAbandonedExited events; it returns zero on other events.
+func (e Event) GetDuration() time.Duration {
+	return e.duration
+}
+
+// AwaitsReadiness returns true on ProcessStarted events of processes that are
+// not ready yet; these processes report a ProcessReady event later on.
+func (e Event) AwaitsReadiness() bool {
+	return e.awaitsReadiness
+}
+
+// GetLabels returns the labels of the process that emitted this event (see
+// WithLabels and WithSupervisorLabels). The returned map must not be modified.
+func (e Event) GetLabels() map[string]string {
+	return e.labels
+}
+
+// GetSourceRuntimeName returns the runtime name of the child that caused a
+// restart on SupervisorRestarting, RestartToleranceIncremented,
+// RestartToleranceWindowReset and ChildRestartScheduled events; it returns an
+// empty string on other events.
+func (e Event) GetSourceRuntimeName() string {
+	if e.restart == nil {
+		return ""
+	}
+	return e.restart.sourceRuntimeName
+}
+
+// GetRestartCount returns the number of restarts accounted on the restart
+// tolerance window of the process that emitted this event. It is only set on
+// SupervisorRestarting, RestartToleranceIncremented,
+// RestartToleranceWindowReset and ChildRestartScheduled events; on
+// ChildRestartScheduled events, it is zero when the process does not have a
+// restart tolerance of its own (see WithTolerance).
+func (e Event) GetRestartCount() uint32 {
+	if e.restart == nil {
+		return 0
+	}
+	return e.restart.restartCount
+}
+
+// GetMaxRestartCount returns the maximum number of restarts allowed on the
+// restart tolerance window of the process that emitted this event. It is only
+// set on SupervisorRestarting, RestartToleranceIncremented,
+// RestartToleranceWindowReset and ChildRestartScheduled events; on
+// ChildRestartScheduled events, it is zero when the process does not have a
+// restart tolerance of its own (see WithTolerance).
+func (e Event) GetMaxRestartCount() uint32 {
+	if e.restart == nil {
+		return 0
+	}
+	return e.restart.maxRestartCount
+}
+
+// GetRestartWindow returns the restart tolerance window of the process that
+// emitted this event. It is only set on SupervisorRestarting,
+// RestartToleranceIncremented, RestartToleranceWindowReset and
+// ChildRestartScheduled events; on ChildRestartScheduled events, it is zero
+// when the process does not have a restart tolerance of its own (see
+// WithTolerance).
+func (e Event) GetRestartWindow() time.Duration {
+	if e.restart == nil {
+		return 0
+	}
+	return e.restart.restartWindow
+}
+
+// GetRestartDelay returns the time a process is going to wait before it gets
+// restarted on ChildRestartScheduled events.
+func (e Event) GetRestartDelay() time.Duration {
+	if e.restart == nil {
+		return 0
+	}
+	return e.restart.restartDelay
+}
+
+// GetRestartStrategy returns the restart strategy the supervisor is going to
+// execute on SupervisorRestarting events.
+func (e Event) GetRestartStrategy() Strategy {
+	if e.restart == nil {
+		return OneForOne
+	}
+	return e.restart.strategy
+}
+
 // String returns an string representation for the Event
 func (e Event) String() string {
 	var buffer strings.Builder
@@ -91,6 +250,29 @@ func (e Event) String() string {
 	buffer.WriteString(fmt.Sprintf(", tag: %20s", e.tag))
 	buffer.WriteString(fmt.Sprintf(", nodeTag: %10s", e.nodeTag))
 	buffer.WriteString(fmt.Sprintf(", processRuntime: %s", e.processRuntimeName))
+	if e.awaitsReadiness {
+		buffer.WriteString(", awaitsReadiness: true")
+	}
+	if len(e.labels) > 0 {
+		buffer.WriteString(fmt.Sprintf(", labels: %v", e.labels))
+	}
+	if e.restart != nil {
+		buffer.WriteString(fmt.Sprintf(", source: %s", e.restart.sourceRuntimeName))
+		buffer.WriteString(
+			fmt.Sprintf(
+				", restartCount: %d/%d, restartWindow: %v",
+				e.restart.restartCount,
+				e.restart.maxRestartCount,
+				e.restart.restartWindow,
+			),
+		)
+		switch e.tag {
+		case SupervisorRestarting:
+			buffer.WriteString(fmt.Sprintf(", strategy: %s", e.restart.strategy))
+		case ChildRestartScheduled:
+			buffer.WriteString(fmt.Sprintf(", restartDelay: %v", e.restart.restartDelay))
+		}
+	}
 	if e.err != nil {
 		buffer.WriteString(fmt.Sprintf(", err: %+v", e.err))
 	}
@@ -122,6 +304,168 @@ func (en EventNotifier) processTerminated(
 	})
 }
 
+// processForceTerminated reports an event with an EventTag of
+// ProcessForceTerminated
+func (en EventNotifier) processForceTerminated(
+	nodeTag c.ChildTag,
+	name string,
+	stopTime time.Time,
+) {
+	createdTime := time.Now()
+	stopDuration := createdTime.Sub(stopTime)
+
+	en(Event{
+		tag:                ProcessForceTerminated,
+		nodeTag:            nodeTag,
+		processRuntimeName: name,
+		created:            createdTime,
+		duration:           stopDuration,
+	})
+}
+
+// processDrained reports an event with an EventTag of ProcessDrained
+func (en EventNotifier) processDrained(nodeTag c.ChildTag, name string) {
+	en(Event{
+		tag:                ProcessDrained,
+		nodeTag:            nodeTag,
+		processRuntimeName: name,
+		created:            time.Now(),
+	})
+}
+
+// processReady reports an event with an EventTag of ProcessReady
+func (en EventNotifier) processReady(nodeTag c.ChildTag, name string) {
+	en(Event{
+		tag:                ProcessReady,
+		nodeTag:            nodeTag,
+		processRuntimeName: name,
+		created:            time.Now(),
+	})
+}
+
+// processAbandoned reports an event with an EventTag of ProcessAbandoned
+func (en EventNotifier) processAbandoned(nodeTag c.ChildTag, name string) {
+	en(Event{
+		tag:                ProcessAbandoned,
+		nodeTag:            nodeTag,
+		processRuntimeName: name,
+		created:            time.Now(),
+	})
+}
+
+// processAbandonedExited reports an event with an EventTag of
+// ProcessAbandonedExited, the duration of the event is the time the process
+// goroutine kept running after it got abandoned
+func (en EventNotifier) processAbandonedExited(
+	nodeTag c.ChildTag,
+	name string,
+	abandonTime time.Time,
+) {
+	createdTime := time.Now()
+	en(Event{
+		tag:                ProcessAbandonedExited,
+		nodeTag:            nodeTag,
+		processRuntimeName: name,
+		created:            createdTime,
+		duration:           createdTime.Sub(abandonTime),
+	})
+}
+
+// withLabels returns an EventNotifier that attaches the given labels to the
+// events it reports
+func (en EventNotifier) withLabels(labels map[string]string) EventNotifier {
+	if en == nil || len(labels) == 0 {
+		return en
+	}
+	return func(ev Event) {
+		ev.labels = labels
+		en(ev)
+	}
+}
+
+// newRestartInfo builds the restart bookkeeping of an event from the given
+// restart tolerance state
+func newRestartInfo(sourceName string, mgr *restartToleranceManager) *restartInfo {
+	return &restartInfo{
+		sourceRuntimeName: sourceName,
+		restartCount:      mgr.restartCount,
+		maxRestartCount:   mgr.restartTolerance.MaxRestartCount,
+		restartWindow:     mgr.restartTolerance.RestartWindow,
+	}
+}
+
+// supervisorRestarting reports an event with an EventTag of
+// SupervisorRestarting
+func (en EventNotifier) supervisorRestarting(
+	name string,
+	sourceName string,
+	strategy Strategy,
+	mgr *restartToleranceManager,
+) {
+	info := newRestartInfo(sourceName, mgr)
+	info.strategy = strategy
+	en(Event{
+		tag:                SupervisorRestarting,
+		nodeTag:            c.Supervisor,
+		processRuntimeName: name,
+		created:            time.Now(),
+		restart:            info,
+	})
+}
+
+// restartToleranceUpdated reports an event with an EventTag of
+// RestartToleranceIncremented or RestartToleranceWindowReset, depending on the
+// result of a restart tolerance check
+func (en EventNotifier) restartToleranceUpdated(
+	nodeTag c.ChildTag,
+	name string,
+	sourceName string,
+	check restartToleranceResult,
+	mgr *restartToleranceManager,
+) {
+	var tag EventTag
+	switch check {
+	case incRestartCount:
+		tag = RestartToleranceIncremented
+	case resetRestartCount:
+		tag = RestartToleranceWindowReset
+	default:
+		return
+	}
+	en(Event{
+		tag:                tag,
+		nodeTag:            nodeTag,
+		processRuntimeName: name,
+		created:            time.Now(),
+		restart:            newRestartInfo(sourceName, mgr),
+	})
+}
+
+// childRestartScheduled reports an event with an EventTag of
+// ChildRestartScheduled
+func (en EventNotifier) childRestartScheduled(ch c.Child, delay time.Duration) {
+	name := ch.GetRuntimeName()
+	info := &restartInfo{
+		sourceRuntimeName: name,
+		restartDelay:      delay,
+	}
+	// the restart bookkeeping comes from the restart tolerance of the child,
+	// the supervisor tolerance is reported on its own events
+	if chTolerance := ch.GetSpec().GetTolerance(); chTolerance != nil {
+		errCount, _, _ := ch.GetToleranceState()
+		info.restartCount = errCount
+		info.maxRestartCount = chTolerance.GetMaxErrCount()
+		info.restartWindow = chTolerance.GetErrWindow()
+	}
+	en(Event{
+		tag:                ChildRestartScheduled,
+		nodeTag:            ch.GetTag(),
+		processRuntimeName: name,
+		created:            time.Now(),
+		restart:            info,
+	})
+}
+
 // supervisorTerminated reports an event with an EventTag of ProcessTerminated
 func (en EventNotifier) supervisorTerminated(name string, stopTime time.Time) {
 	en.processTerminated(c.Supervisor, name, stopTime)
@@ -167,6 +511,22 @@ func (en EventNotifier) workerFailed(name string, err error) {
 //	en.processFailed(c.Worker, name, err)
 // }
 
+// childRestartToleranceReached reports an event with an EventTag of
+// ChildRestartToleranceReached
+func (en EventNotifier) childRestartToleranceReached(
+	nodeTag c.ChildTag,
+	name string,
+	err error,
+) {
+	en(Event{
+		tag:                ChildRestartToleranceReached,
+		nodeTag:            nodeTag,
+		processRuntimeName: name,
+		err:                err,
+		created:            time.Now(),
+	})
+}
+
 // processStartFailed reports an event with an EventTag of ProcessStartFailed
 func (en EventNotifier) processStartFailed(
 	nodeTag c.ChildTag,
@@ -214,6 +574,20 @@ func (en EventNotifier) workerStarted(name string, startTime time.Time) {
 	processStarted(en, c.Worker, name, startTime)
 }
 
+// workerStartedNotReady reports an event with an EventTag of ProcessStarted for
+// a worker that reports it is ready later on
+func (en EventNotifier) workerStartedNotReady(name string, startTime time.Time) {
+	createdTime := time.Now()
+	en(Event{
+		tag:                ProcessStarted,
+		nodeTag:            c.Worker,
+		processRuntimeName: name,
+		created:            createdTime,
+		duration:           createdTime.Sub(startTime),
+		awaitsReadiness:    true,
+	})
+}
+
 // emptyEventNotifier is an utility function that works as a default value
 // whenever an EventNotifier is not specified on the Supervisor Spec
 func emptyEventNotifier(_ Event) {}
@@ -0,0 +1,190 @@
+package s_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	// ctxErrCh receives the context error of the worker once it finished its
+	// in-flight work
+	ctxErrCh := make(chan error, 1)
+
+	child1 := cap.NewWorker(
+		"child1",
+		func(ctx context.Context) error {
+			<-cap.ShutdownRequested(ctx)
+			// finish in-flight work
+			time.Sleep(20 * time.Millisecond)
+			ctxErrCh <- ctx.Err()
+			return nil
+		},
+		cap.WithShutdown(cap.Graceful(time.Second, cap.Timeout(10*time.Millisecond))),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		func(em EventManager) {},
+	)
+
+	assert.NoError(t, err)
+	// the context is not canceled within the grace period
+	assert.NoError(t, <-ctxErrCh)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestGracefulShutdownGracePeriodOver(t *testing.T) {
+	stopRequestedCh := make(chan struct{})
+
+	child1 := cap.NewWorker(
+		"child1",
+		func(ctx context.Context) error {
+			<-cap.ShutdownRequested(ctx)
+			close(stopRequestedCh)
+			// the worker ignores the stop request
+			<-ctx.Done()
+			return nil
+		},
+		cap.WithShutdown(cap.Graceful(20*time.Millisecond, cap.Timeout(time.Second))),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		func(em EventManager) {},
+	)
+
+	assert.NoError(t, err)
+	<-stopRequestedCh
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestGracefulShutdownTimeout(t *testing.T) {
+	releaseCh := make(chan struct{})
+	defer close(releaseCh)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(
+			hangingWorker(
+				"child1",
+				releaseCh,
+				cap.WithShutdown(
+					cap.Graceful(10*time.Millisecond, cap.Timeout(10*time.Millisecond)),
+				),
+			),
+		),
+		[]cap.Opt{},
+		func(em EventManager) {},
+	)
+
+	assert.Error(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			WorkerFailedWith("root/child1", "child shutdown timeout"),
+			WorkerAbandoned("root/child1"),
+			SupervisorFailed("root"),
+		},
+	)
+}
+
+func TestShutdownRequestedWithoutGraceful(t *testing.T) {
+	child1 := cap.NewWorker(
+		"child1",
+		func(ctx context.Context) error {
+			// without a Graceful shutdown, the context cancelation is the stop
+			// request
+			<-cap.ShutdownRequested(ctx)
+			return nil
+		},
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		func(em EventManager) {},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestGracefulShutdownSubtree(t *testing.T) {
+	branch1 := cap.NewSupervisorSpec(
+		"branch1",
+		cap.WithNodes(WaitDoneWorker("child1")),
+		cap.WithSupervisorShutdown(cap.Graceful(time.Minute, cap.Indefinitely)),
+	)
+
+	startTime := time.Now()
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(cap.Subtree(branch1)),
+		[]cap.Opt{},
+		func(em EventManager) {},
+	)
+
+	assert.NoError(t, err)
+	// the sub-tree terminates on the stop request, rather than after the grace
+	// period
+	assert.True(t, time.Since(startTime) < time.Second)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/branch1/child1"),
+			SupervisorStarted("root/branch1"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/branch1/child1"),
+			SupervisorTerminated("root/branch1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestGracefulInvalid(t *testing.T) {
+	assert.Panics(t, func() {
+		cap.Graceful(0, cap.Indefinitely)
+	})
+}
@@ -9,6 +9,7 @@ import (
 type HealthReport struct {
 	failedProcesses         map[string]bool
 	delayedRestartProcesses map[string]bool
+	notReadyProcesses       map[string]bool
 }
 
 // HealthyReport represents a healthy report
@@ -21,6 +22,7 @@ type HealthcheckMonitor struct {
 	maxAllowedRestartDuration time.Duration
 	maxAllowedFailures        uint32
 	failedEvs                 map[string]Event
+	notReadyEvs               map[string]Event
 }
 
 // GetFailedProcesses returns a list of the failed processes
@@ -33,11 +35,24 @@ func (hr HealthReport) GetDelayedRestartProcesses() map[string]bool {
 	return hr.delayedRestartProcesses
 }
 
-// IsHealthyReport indicates if this is a healthy report
+// GetNotReadyProcesses returns a list of the processes that started and did
+// not report they are ready yet (see WithReadiness)
+func (hr HealthReport) GetNotReadyProcesses() map[string]bool {
+	return hr.notReadyProcesses
+}
+
+// IsHealthyReport indicates if this is a healthy report. Processes that are
+// not ready yet do not make a report unhealthy.
 func (hr HealthReport) IsHealthyReport() bool {
 	return len(hr.failedProcesses) == 0 && len(hr.delayedRestartProcesses) == 0
 }
 
+// IsReadyReport indicates if all the started processes of this report are
+// ready
+func (hr HealthReport) IsReadyReport() bool {
+	return len(hr.notReadyProcesses) == 0
+}
+
 // NewHealthcheckMonitor offers a way to monitor a supervision tree health from
 // events emitted by it.
 //
@@ -57,6 +72,7 @@ func NewHealthcheckMonitor(
 		maxAllowedRestartDuration: maxAllowedRestartDuration,
 		maxAllowedFailures:        maxAllowedFailures,
 		failedEvs:                 make(map[string]Event),
+		notReadyEvs:               make(map[string]Event),
 	}
 }
 
@@ -72,6 +88,24 @@ func (h *HealthcheckMonitor) HandleEvent(ev Event) {
 	case ProcessStarted:
 		delete(h.failedEvs, ev.GetProcessRuntimeName())
 	}
+
+	// a process is not ready from its start until it reports it is ready or it
+	// finishes; other events of the process (e.g. the ProcessAbandonedExited
+	// event of a previous run) do not change its readiness
+	switch ev.GetTag() {
+	case ProcessStarted:
+		if ev.AwaitsReadiness() {
+			h.notReadyEvs[ev.GetProcessRuntimeName()] = ev
+		}
+	case ProcessReady,
+		ProcessFailed,
+		ProcessCompleted,
+		ProcessTerminated,
+		ProcessForceTerminated,
+		ProcessStartFailed,
+		ProcessDrained:
+		delete(h.notReadyEvs, ev.GetProcessRuntimeName())
+	}
 }
 
 // GetHealthReport returns a string that indicates why a the system
@@ -81,13 +115,18 @@ func (h *HealthcheckMonitor) GetHealthReport() HealthReport {
 	defer h.mu.Unlock()
 
 	// if there is an acceptable number of failures, things are healthy
-	if uint32(len(h.failedEvs)) == 0 {
+	if uint32(len(h.failedEvs)) == 0 && len(h.notReadyEvs) == 0 {
 		return HealthyReport
 	}
 
 	hr := HealthReport{
 		failedProcesses:         make(map[string]bool),
 		delayedRestartProcesses: make(map[string]bool),
+		notReadyProcesses:       make(map[string]bool),
+	}
+
+	for processName := range h.notReadyEvs {
+		hr.notReadyProcesses[processName] = true
 	}
 
 	// if you have more than maxAllowedFailures process failing, then you are
@@ -116,3 +155,9 @@ func (h *HealthcheckMonitor) GetHealthReport() HealthReport {
 func (h *HealthcheckMonitor) IsHealthy() bool {
 	return h.GetHealthReport().IsHealthyReport()
 }
+
+// IsReady return true when all the started processes of the system reported
+// they are ready
+func (h *HealthcheckMonitor) IsReady() bool {
+	return h.GetHealthReport().IsReadyReport()
+}
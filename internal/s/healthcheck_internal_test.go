@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/internal/c"
 )
 
 func TestHealthNothingToDo(t *testing.T) {
@@ -131,3 +133,37 @@ func TestHealthRestoredReport(t *testing.T) {
 	notifier.workerStarted("w1", time.Now())
 	assert.True(t, healthcheckMonitor.GetHealthReport().IsHealthyReport())
 }
+
+func TestNotReadyReport(t *testing.T) {
+	healthcheckMonitor := NewHealthcheckMonitor(0, 0*time.Millisecond)
+
+	var notifier EventNotifier = func(ev Event) {
+		healthcheckMonitor.HandleEvent(ev)
+	}
+
+	notifier.workerStartedNotReady("w1", time.Now())
+	notifier.workerStartedNotReady("w2", time.Now())
+	notifier.workerStarted("w3", time.Now())
+
+	hr := healthcheckMonitor.GetHealthReport()
+	// Processes that are not ready are healthy
+	assert.True(t, hr.IsHealthyReport())
+	assert.False(t, hr.IsReadyReport())
+	assert.Equal(t, map[string]bool{"w1": true, "w2": true}, hr.GetNotReadyProcesses())
+
+	notifier.processReady(c.Worker, "w1")
+	// a process that fails before it is ready is not reported as not ready
+	notifier.workerFailed("w2", errors.New("w2 error"))
+
+	hr = healthcheckMonitor.GetHealthReport()
+	assert.False(t, hr.IsHealthyReport())
+	assert.True(t, hr.IsReadyReport())
+
+	notifier.workerStartedNotReady("w2", time.Now())
+	assert.True(t, healthcheckMonitor.IsHealthy())
+	assert.False(t, healthcheckMonitor.IsReady())
+
+	notifier.processReady(c.Worker, "w2")
+	assert.True(t, healthcheckMonitor.IsReady())
+	assert.Equal(t, HealthyReport, healthcheckMonitor.GetHealthReport())
+}
@@ -139,6 +139,7 @@ func TestHealthTerminateFailedChild(t *testing.T) {
 			WorkerTerminated("root/branch1/child3"),
 			// NOTE: the child2 never stops and fails with a timeout
 			WorkerFailed("root/branch1/child2"),
+			WorkerAbandoned("root/branch1/child2"),
 			// NOTE: The supervisor branch1 fails because of child2 timeout
 			SupervisorFailed("root/branch1"),
 			WorkerTerminated("root/branch0/child1"),
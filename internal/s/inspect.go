@@ -0,0 +1,349 @@
+package s
+
+// This file contains the implementation of the supervision tree introspection
+// API
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/capatazlib/go-capataz/internal/c"
+)
+
+// NodeState specifies the runtime state of a node in a supervision tree
+type NodeState uint32
+
+const (
+	// NodeRunning indicates the node goroutine is running
+	NodeRunning NodeState = iota
+	// NodeRestarting indicates the node goroutine finished and it is waiting
+	// for a delayed restart (see WithRestartBackoff)
+	NodeRestarting
+	// NodeStopped indicates the node goroutine finished and it is not going to
+	// be restarted by its supervisor (e.g. a Transient node that completed)
+	NodeStopped
+)
+
+// String returns a string representation of the current NodeState
+func (ns NodeState) String() string {
+	switch ns {
+	case NodeRunning:
+		return "Running"
+	case NodeRestarting:
+		return "Restarting"
+	case NodeStopped:
+		return "Stopped"
+	default:
+		return "<Unknown>"
+	}
+}
+
+// NodeSnapshot contains the runtime information of a node in a supervision
+// tree at the moment a snapshot was taken.
+type NodeSnapshot struct {
+	name         string
+	runtimeName  string
+	tag          c.ChildTag
+	restart      c.Restart
+	shutdown     c.Shutdown
+	restartCount uint32
+	createdAt    time.Time
+	takenAt      time.Time
+	state        NodeState
+	children     []NodeSnapshot
+
+	// ctrl is used to inspect the children of sub-tree nodes
+	ctrl supervisorCtrl
+}
+
+// GetName returns the spec name of the node
+func (ns NodeSnapshot) GetName() string {
+	return ns.name
+}
+
+// GetRuntimeName returns the runtime name of the node
+func (ns NodeSnapshot) GetRuntimeName() string {
+	return ns.runtimeName
+}
+
+// GetTag returns the c.ChildTag of the node
+func (ns NodeSnapshot) GetTag() c.ChildTag {
+	return ns.tag
+}
+
+// GetRestart returns the c.Restart setting of the node
+func (ns NodeSnapshot) GetRestart() c.Restart {
+	return ns.restart
+}
+
+// GetShutdown returns the c.Shutdown setting of the node
+func (ns NodeSnapshot) GetShutdown() c.Shutdown {
+	return ns.shutdown
+}
+
+// GetRestartCount returns the number of times the node was restarted by its
+// supervisor
+func (ns NodeSnapshot) GetRestartCount() uint32 {
+	return ns.restartCount
+}
+
+// GetCreatedAt returns the timestamp of when the current runtime of the node
+// was started
+func (ns NodeSnapshot) GetCreatedAt() time.Time {
+	return ns.createdAt
+}
+
+// GetUptime returns for how long the node had been running when the snapshot
+// was taken. Nodes that are not running have an uptime of zero.
+func (ns NodeSnapshot) GetUptime() time.Duration {
+	if ns.state != NodeRunning {
+		return 0
+	}
+	return ns.takenAt.Sub(ns.createdAt)
+}
+
+// GetState returns the NodeState of the node
+func (ns NodeSnapshot) GetState() NodeState {
+	return ns.state
+}
+
+// GetChildren returns the snapshots of the children of a supervisor node. Nodes
+// that are workers or supervisors that are not running have no children.
+func (ns NodeSnapshot) GetChildren() []NodeSnapshot {
+	return ns.children
+}
+
+// String returns an string representation for the NodeSnapshot
+func (ns NodeSnapshot) String() string {
+	return fmt.Sprintf(
+		"NodeSnapshot{name: %s, tag: %s, state: %s, restart: %s, shutdown: %s, restartCount: %d, uptime: %v}",
+		ns.runtimeName, ns.tag, ns.state, ns.restart, ns.shutdown, ns.restartCount, ns.GetUptime(),
+	)
+}
+
+// TreeSnapshot contains the runtime information of all the nodes of a
+// supervision tree.
+//
+// Every supervisor of the tree reports the state of its own children from its
+// own goroutine, given this, a TreeSnapshot is consistent per supervisor, but
+// sub-trees may be inspected at slightly different moments.
+type TreeSnapshot struct {
+	root NodeSnapshot
+}
+
+// GetRoot returns the snapshot of the root supervisor of the tree
+func (ts TreeSnapshot) GetRoot() NodeSnapshot {
+	return ts.root
+}
+
+// Flatten returns the snapshots of all the nodes in the tree, in pre-order
+func (ts TreeSnapshot) Flatten() []NodeSnapshot {
+	var acc []NodeSnapshot
+	var walk func(NodeSnapshot)
+	walk = func(ns NodeSnapshot) {
+		acc = append(acc, ns)
+		for _, ch := range ns.children {
+			walk(ch)
+		}
+	}
+	walk(ts.root)
+	return acc
+}
+
+// Find returns the snapshot of the node with the given runtime name
+func (ts TreeSnapshot) Find(runtimeName string) (NodeSnapshot, bool) {
+	for _, ns := range ts.Flatten() {
+		if ns.runtimeName == runtimeName {
+			return ns, true
+		}
+	}
+	return NodeSnapshot{}, false
+}
+
+// inspectMsg is a message sent from clients to get a snapshot of the children
+// of a supervisor.
+type inspectMsg struct {
+	resultChan chan<- []NodeSnapshot
+}
+
+func (im inspectMsg) processMsg(
+	_ context.Context,
+	_ EventNotifier,
+	spec SupervisorSpec,
+	specChildren []c.ChildSpec,
+	supRuntimeName string,
+	supChildren map[string]c.Child,
+	_ chan c.ChildNotification,
+	supScheduler *restartScheduler,
+) ([]c.ChildSpec, map[string]c.Child) {
+	// REMEMBER: WE ARE RUNNING THIS CODE IN THE SUPERVISOR THREAD
+
+	takenAt := time.Now()
+	snapshots := make([]NodeSnapshot, 0, len(specChildren))
+
+	for _, chSpec := range spec.order.sortStart(specChildren) {
+		ns := NodeSnapshot{
+			name:        chSpec.GetName(),
+			runtimeName: buildChildRuntimeName(supRuntimeName, chSpec),
+			tag:         chSpec.GetTag(),
+			restart:     chSpec.GetRestart(),
+			shutdown:    chSpec.Shutdown,
+			takenAt:     takenAt,
+			state:       NodeStopped,
+		}
+
+		if ch, ok := supChildren[chSpec.GetName()]; ok {
+			ns.restartCount = ch.GetRestartCount()
+			ns.createdAt = ch.GetCreatedAt()
+			if supScheduler.isPending(supChildren, chSpec.GetName()) {
+				ns.state = NodeRestarting
+			} else {
+				ns.state = NodeRunning
+				ns.ctrl, _ = newSubtreeCtrl(chSpec, ch)
+			}
+		}
+
+		snapshots = append(snapshots, ns)
+	}
+
+	// do not block waiting for a read
+	select {
+	case im.resultChan <- snapshots:
+	default:
+	}
+
+	return specChildren, supChildren
+}
+
+var _ ctrlMsg = inspectMsg{}
+
+// supervisorCtrl allows clients to send control messages to the monitor loop
+// of a supervisor. The ctrlChan of a root supervisor is closed when it
+// terminates; the ctrlChan of a sub-tree is shared by all the runs of the
+// sub-tree and it is never closed, instead, the doneCh of the run that was
+// resolved is closed when its monitor loop is done.
+type supervisorCtrl struct {
+	ctrlChan chan ctrlMsg
+	doneCh   <-chan struct{}
+}
+
+// newSubtreeCtrl returns the supervisorCtrl of the current run of a sub-tree
+// child, it returns false if the child is not a sub-tree.
+func newSubtreeCtrl(chSpec c.ChildSpec, ch c.Child) (supervisorCtrl, bool) {
+	ctrlChan, isSubtree := chSpec.GetCtrl().(chan ctrlMsg)
+	if !isSubtree {
+		return supervisorCtrl{}, false
+	}
+	return supervisorCtrl{ctrlChan: ctrlChan, doneCh: ch.Done()}, true
+}
+
+// sendCtrlMsg sends a control message to a supervisor, it returns an error if
+// the supervisor is terminated or if it is not able to receive the message
+// before the given context is done.
+func sendCtrlMsg(ctx context.Context, ctrlChan chan ctrlMsg, msg ctrlMsg) error {
+	return supervisorCtrl{ctrlChan: ctrlChan}.send(ctx, msg)
+}
+
+// send sends a control message to the supervisor, it returns an error if the
+// supervisor is done or if it is not able to receive the message before the
+// given context is done.
+func (sc supervisorCtrl) send(ctx context.Context, msg ctrlMsg) (err error) {
+	defer func() {
+		panicVal := recover()
+		if panicVal == nil {
+			return
+		}
+
+		if panicErr, ok := panicVal.(error); ok {
+			err = fmt.Errorf("could not talk to supervisor: %w", panicErr)
+			return
+		}
+
+		// retrigger panic, this would happen on an implementation error
+		panic(panicVal)
+	}()
+
+	// in case the root supervisor is stopped, this line is going to panic
+	select {
+	case sc.ctrlChan <- msg:
+		return nil
+	case <-sc.doneCh:
+		return errors.New("could not talk to supervisor: supervisor terminated")
+	case <-ctx.Done():
+		return fmt.Errorf("could not talk to supervisor: %w", ctx.Err())
+	}
+}
+
+// inspectChildren gets the snapshots of the children of the supervisor that
+// listens on the given supervisorCtrl, and it recursively inspects the children
+// of its sub-trees.
+func inspectChildren(ctx context.Context, ctrl supervisorCtrl) ([]NodeSnapshot, error) {
+	// REMEMBER: WE ARE RUNNING ON THE CLIENT API THREAD
+
+	// we initialize the resultChan with a buffer of 1, we may store the result
+	// before the client is ready to read it.
+	resultChan := make(chan []NodeSnapshot, 1)
+
+	err := ctrl.send(ctx, inspectMsg{resultChan: resultChan})
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []NodeSnapshot
+	select {
+	case snapshots = <-resultChan:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("could not get a snapshot from supervisor: %w", ctx.Err())
+	}
+
+	for i, ns := range snapshots {
+		if ns.ctrl.ctrlChan == nil {
+			continue
+		}
+		children, err := inspectChildren(ctx, ns.ctrl)
+		if err != nil {
+			return nil, err
+		}
+		snapshots[i].children = children
+	}
+
+	return snapshots, nil
+}
+
+// Inspect returns a snapshot of the runtime state of all the nodes in the
+// supervision tree. This function blocks until every supervisor in the tree
+// reports its children, or until the given context is done.
+func (sup Supervisor) Inspect(ctx context.Context) (TreeSnapshot, error) {
+	if terminated, _ := sup.terminateManager.getTerminateErr(); terminated {
+		return TreeSnapshot{}, errors.New("supervisor already terminated")
+	}
+
+	children, err := inspectChildren(ctx, supervisorCtrl{ctrlChan: sup.ctrlCh})
+	if err != nil {
+		return TreeSnapshot{}, err
+	}
+
+	takenAt := time.Now()
+	return TreeSnapshot{
+		root: NodeSnapshot{
+			name:        sup.spec.GetName(),
+			runtimeName: sup.runtimeName,
+			tag:         c.Supervisor,
+			restart:     c.Permanent,
+			shutdown:    sup.spec.shutdown,
+			createdAt:   sup.createdAt,
+			takenAt:     takenAt,
+			state:       NodeRunning,
+			children:    children,
+		},
+	}, nil
+}
+
+// Inspect returns a snapshot of the runtime state of all the nodes in the
+// supervision tree. This function blocks until every supervisor in the tree
+// reports its children, or until the given context is done.
+func (dyn DynSupervisor) Inspect(ctx context.Context) (TreeSnapshot, error) {
+	return dyn.sup.Inspect(ctx)
+}
@@ -0,0 +1,55 @@
+package s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSubtreeTree starts a supervisor with a single sub-tree, and it returns
+// the supervisorCtrl of the sub-tree
+func startSubtreeTree(ctx context.Context, t *testing.T) (Supervisor, supervisorCtrl) {
+	worker := NewWorker("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	spec := NewSupervisorSpec(
+		"root",
+		WithNodes(Subtree(NewSupervisorSpec("subtree", WithNodes(worker)))),
+	)
+	sup, err := spec.Start(ctx)
+	require.NoError(t, err)
+
+	snapshot, err := sup.Inspect(ctx)
+	require.NoError(t, err)
+	subtreeSnapshot, ok := snapshot.Find("root/subtree")
+	require.True(t, ok)
+	require.NotNil(t, subtreeSnapshot.ctrl.ctrlChan)
+
+	return sup, subtreeSnapshot.ctrl
+}
+
+func TestInspectTerminatedSubtree(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	sup, ctrl := startSubtreeTree(ctx, t)
+	require.NoError(t, sup.Terminate())
+
+	// messages to a terminated sub-tree do not block, even without a deadline
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := inspectChildren(context.Background(), ctrl)
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("inspect of a terminated sub-tree is blocked")
+	}
+}
@@ -0,0 +1,140 @@
+package s_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestInspectSupervisorTree(t *testing.T) {
+	child3, completeWorker3 := CompleteOnSignalWorker(
+		1, "child3", cap.WithRestart(cap.Transient),
+	)
+	subtree := cap.NewSupervisorSpec(
+		"subtree",
+		cap.WithNodes(WaitDoneWorker("child2"), child3),
+	)
+
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	spec := cap.NewSupervisorSpec(
+		"root",
+		cap.WithNodes(WaitDoneWorker("child1"), cap.Subtree(subtree)),
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+
+	sup, err := spec.Start(ctx)
+	assert.NoError(t, err)
+
+	evIt := evManager.Iterator()
+	completeWorker3()
+	evIt.SkipTill(WorkerCompleted("root/subtree/child3"))
+
+	snapshot, err := sup.Inspect(ctx)
+	assert.NoError(t, err)
+
+	root := snapshot.GetRoot()
+	assert.Equal(t, "root", root.GetRuntimeName())
+	assert.Equal(t, cap.SupervisorT, root.GetTag())
+	assert.Len(t, root.GetChildren(), 2)
+
+	names := make([]string, 0)
+	for _, ns := range snapshot.Flatten() {
+		names = append(names, ns.GetRuntimeName())
+	}
+	assert.Equal(
+		t,
+		[]string{"root", "root/child1", "root/subtree", "root/subtree/child2", "root/subtree/child3"},
+		names,
+	)
+
+	subtreeSnapshot, ok := snapshot.Find("root/subtree")
+	assert.True(t, ok)
+	assert.Equal(t, cap.SupervisorT, subtreeSnapshot.GetTag())
+	assert.Equal(t, cap.NodeRunning, subtreeSnapshot.GetState())
+	assert.Equal(t, cap.Indefinitely, subtreeSnapshot.GetShutdown())
+	assert.Len(t, subtreeSnapshot.GetChildren(), 2)
+
+	child1Snapshot, ok := snapshot.Find("root/child1")
+	assert.True(t, ok)
+	assert.Equal(t, cap.WorkerT, child1Snapshot.GetTag())
+	assert.Equal(t, cap.Permanent, child1Snapshot.GetRestart())
+	assert.Equal(t, cap.Timeout(5*time.Second), child1Snapshot.GetShutdown())
+	assert.Equal(t, cap.NodeRunning, child1Snapshot.GetState())
+	assert.True(t, child1Snapshot.GetUptime() > 0)
+
+	child3Snapshot, ok := snapshot.Find("root/subtree/child3")
+	assert.True(t, ok)
+	assert.Equal(t, cap.NodeStopped, child3Snapshot.GetState())
+	assert.Equal(t, time.Duration(0), child3Snapshot.GetUptime())
+
+	assert.NoError(t, sup.Terminate())
+
+	_, err = sup.Inspect(ctx)
+	assert.Error(t, err)
+}
+
+func TestInspectRestartCount(t *testing.T) {
+	child1, failWorker1 := FailOnSignalWorker(
+		2, "child1", cap.WithRestart(cap.Permanent),
+	)
+
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	spec := cap.NewSupervisorSpec(
+		"root",
+		cap.WithNodes(child1),
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+		cap.WithRestartTolerance(10, 10*time.Second),
+	)
+
+	sup, err := spec.Start(ctx)
+	assert.NoError(t, err)
+
+	evIt := evManager.Iterator()
+	evIt.SkipTill(SupervisorStarted("root"))
+	failWorker1(false /* done */)
+	evIt.SkipTill(WorkerStarted("root/child1"))
+	failWorker1(false /* done */)
+	evIt.SkipTill(WorkerStarted("root/child1"))
+
+	snapshot, err := sup.Inspect(ctx)
+	assert.NoError(t, err)
+
+	child1Snapshot, ok := snapshot.Find("root/child1")
+	assert.True(t, ok)
+	assert.Equal(t, uint32(2), child1Snapshot.GetRestartCount())
+
+	assert.NoError(t, sup.Terminate())
+}
+
+func TestInspectDynSupervisor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	dyn, err := cap.NewDynSupervisor(ctx, "root")
+	assert.NoError(t, err)
+
+	_, err = dyn.Spawn(WaitDoneWorker("child1"))
+	assert.NoError(t, err)
+
+	snapshot, err := dyn.Inspect(ctx)
+	assert.NoError(t, err)
+
+	child1Snapshot, ok := snapshot.Find("root/child1")
+	assert.True(t, ok)
+	assert.Equal(t, cap.NodeRunning, child1Snapshot.GetState())
+
+	assert.NoError(t, dyn.Terminate())
+}
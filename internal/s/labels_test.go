@@ -0,0 +1,149 @@
+package s_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+// labelsCollector returns a notifier that keeps track of the labels of the
+// events emitted by each process
+func labelsCollector() (cap.EventNotifier, func(string) []map[string]string) {
+	var mux sync.Mutex
+	labels := make(map[string][]map[string]string)
+
+	notifier := func(ev cap.Event) {
+		mux.Lock()
+		defer mux.Unlock()
+		name := ev.GetProcessRuntimeName()
+		labels[name] = append(labels[name], ev.GetLabels())
+	}
+
+	getLabels := func(name string) []map[string]string {
+		mux.Lock()
+		defer mux.Unlock()
+		return labels[name]
+	}
+
+	return notifier, getLabels
+}
+
+func TestLabels(t *testing.T) {
+	subtree1 := cap.NewSupervisorSpec(
+		"subtree1",
+		cap.WithNodes(WaitDoneWorker("child2")),
+		cap.WithSupervisorLabels(map[string]string{"component": "queue"}),
+	)
+
+	notifier, getLabels := labelsCollector()
+
+	events, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(
+			cap.NewWorker(
+				"child1",
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				},
+				cap.WithLabels(map[string]string{"team": "billing"}),
+			),
+			cap.Subtree(subtree1, cap.WithLabels(map[string]string{"tier": "2"})),
+		),
+		[]cap.Opt{
+			cap.WithSupervisorLabels(map[string]string{"team": "platform", "tier": "1"}),
+		},
+		[]cap.EventNotifier{notifier},
+		func(EventManager) {},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/subtree1/child2"),
+			SupervisorStarted("root/subtree1"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/subtree1/child2"),
+			SupervisorTerminated("root/subtree1"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+
+	rootLabels := map[string]string{"team": "platform", "tier": "1"}
+	// labels of a worker win over the inherited ones
+	child1Labels := map[string]string{"team": "billing", "tier": "1"}
+	// sub-trees merge the labels of their parent, their spec, and the ones
+	// given to Subtree
+	subtree1Labels := map[string]string{"team": "platform", "tier": "2", "component": "queue"}
+
+	expected := map[string]map[string]string{
+		"root":                 rootLabels,
+		"root/child1":          child1Labels,
+		"root/subtree1":        subtree1Labels,
+		"root/subtree1/child2": subtree1Labels,
+	}
+
+	for name, expectedLabels := range expected {
+		evLabels := getLabels(name)
+		// started and terminated events
+		assert.Len(t, evLabels, 2, name)
+		for _, labels := range evLabels {
+			assert.Equal(t, expectedLabels, labels, name)
+		}
+	}
+}
+
+func TestLabelsOnRestart(t *testing.T) {
+	child1, failWorker1 := FailOnSignalWorker(
+		1,
+		"child1",
+		cap.WithRestart(cap.Permanent),
+		cap.WithLabels(map[string]string{"team": "billing"}),
+	)
+
+	notifier, getLabels := labelsCollector()
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{
+			cap.WithRestartTolerance(3, 10*time.Second),
+			cap.WithSupervisorLabels(map[string]string{"team": "platform"}),
+		},
+		[]cap.EventNotifier{notifier},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+			failWorker1(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	// started, failed, started and terminated events of the worker
+	child1Labels := getLabels("root/child1")
+	assert.Len(t, child1Labels, 4)
+	for _, labels := range child1Labels {
+		assert.Equal(t, map[string]string{"team": "billing"}, labels)
+	}
+
+	// started, restart tolerance incremented, restarting and terminated events
+	// of the supervisor
+	rootLabels := getLabels("root")
+	assert.Len(t, rootLabels, 4)
+	for _, labels := range rootLabels {
+		assert.Equal(t, map[string]string{"team": "platform"}, labels)
+	}
+}
@@ -0,0 +1,223 @@
+package s_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+// assertLivenessFailure asserts the first failure event of the given events
+// was reported with a LivenessTimeoutError
+func assertLivenessFailure(t *testing.T, events []cap.Event) *cap.LivenessTimeoutError {
+	for _, ev := range events {
+		if ev.GetTag() != cap.ProcessFailed {
+			continue
+		}
+		var livenessErr *cap.LivenessTimeoutError
+		assert.True(t, errors.As(ev.Err(), &livenessErr))
+		return livenessErr
+	}
+	assert.Fail(t, "expected a ProcessFailed event")
+	return nil
+}
+
+func TestHeartbeatMissed(t *testing.T) {
+	beats := make(chan struct{})
+
+	child1 := cap.NewWorker(
+		"child1",
+		func(ctx context.Context) error {
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case _, ok := <-beats:
+					// stop sending heartbeats when the channel is closed
+					if !ok {
+						<-ctx.Done()
+						return nil
+					}
+					cap.Heartbeat(ctx)
+				}
+			}
+		},
+		cap.WithHeartbeat(50*time.Millisecond),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			// heartbeats keep the worker alive
+			for i := 0; i < 5; i++ {
+				time.Sleep(20 * time.Millisecond)
+				beats <- struct{}{}
+			}
+			close(beats)
+
+			evIt.SkipTill(WorkerFailed("root/child1"))
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			WorkerFailedWith("root/child1", "liveness timeout after 50ms"),
+			WorkerStarted("root/child1"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+
+	assertLivenessFailure(t, events)
+}
+
+func TestLivenessProbeFailed(t *testing.T) {
+	probeErr := errors.New("connection lost")
+	var probeCount int32
+
+	child1 := cap.NewWorker(
+		"child1",
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		cap.WithLivenessProbe(
+			10*time.Millisecond,
+			time.Second,
+			func(context.Context) error {
+				if atomic.AddInt32(&probeCount, 1) == 3 {
+					return probeErr
+				}
+				return nil
+			},
+		),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+			evIt.SkipTill(WorkerFailed("root/child1"))
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			WorkerFailedWith("root/child1", "liveness probe failed: connection lost"),
+			WorkerStarted("root/child1"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+
+	livenessErr := assertLivenessFailure(t, events)
+	assert.True(t, errors.Is(livenessErr, probeErr))
+}
+
+func TestLivenessProbeHungWorker(t *testing.T) {
+	// releases the hung worker goroutine
+	releaseCh := make(chan struct{})
+
+	hungCh := make(chan struct{})
+	var hung int32
+
+	child1 := cap.NewWorker(
+		"child1",
+		func(ctx context.Context) error {
+			// only the first instance of the worker hangs
+			if atomic.LoadInt32(&hung) == 1 {
+				atomic.StoreInt32(&hung, 0)
+				<-ctx.Done()
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-hungCh:
+				// the worker does not respect the context anymore
+				atomic.StoreInt32(&hung, 1)
+				<-releaseCh
+				return nil
+			}
+		},
+		cap.WithShutdown(cap.Timeout(10*time.Millisecond)),
+		cap.WithLivenessProbe(
+			10*time.Millisecond,
+			10*time.Millisecond,
+			func(ctx context.Context) error {
+				if atomic.LoadInt32(&hung) == 1 {
+					// the probe does not return on time
+					<-ctx.Done()
+				}
+				return nil
+			},
+		),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"liveness-hung",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("liveness-hung"))
+			close(hungCh)
+			evIt.SkipTill(WorkerFailed("liveness-hung/child1"))
+			evIt.SkipTill(WorkerAbandoned("liveness-hung/child1"))
+			evIt.SkipTill(WorkerStarted("liveness-hung/child1"))
+
+			// the hung goroutine keeps running next to the restarted worker
+			_, ok := findAbandonedProcess("liveness-hung/child1")
+			assert.True(t, ok, "hung worker is not reported as abandoned")
+
+			close(releaseCh)
+			evIt.SkipTill(WorkerAbandonedExited("liveness-hung/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	livenessErr := assertLivenessFailure(t, events)
+	var timeoutErr *cap.ShutdownTimeoutError
+	assert.True(t, errors.As(livenessErr, &timeoutErr))
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("liveness-hung/child1"),
+			SupervisorStarted("liveness-hung"),
+			WorkerFailed("liveness-hung/child1"),
+			WorkerAbandoned("liveness-hung/child1"),
+			WorkerStarted("liveness-hung/child1"),
+			WorkerAbandonedExited("liveness-hung/child1"),
+			WorkerTerminated("liveness-hung/child1"),
+			SupervisorTerminated("liveness-hung"),
+		},
+	)
+}
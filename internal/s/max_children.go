@@ -0,0 +1,47 @@
+package s
+
+// MaxChildrenPolicy specifies what happens when a supervisor is asked to spawn
+// a child and it already supervises the maximum number of children (see
+// WithMaxChildren)
+type MaxChildrenPolicy uint32
+
+const (
+	// RejectSpawn makes the spawn call return a MaxChildrenReachedError
+	RejectSpawn MaxChildrenPolicy = iota
+	// WaitForSlot makes the spawn call block until one of the children of the
+	// supervisor finishes, or until the context of the spawn call is done
+	WaitForSlot
+)
+
+// String returns a string representation of the current MaxChildrenPolicy
+func (p MaxChildrenPolicy) String() string {
+	switch p {
+	case RejectSpawn:
+		return "RejectSpawn"
+	case WaitForSlot:
+		return "WaitForSlot"
+	default:
+		return "<Unknown>"
+	}
+}
+
+// childrenLimit keeps the maximum number of children a supervisor may spawn at
+// runtime.
+type childrenLimit struct {
+	maxChildren int
+	policy      MaxChildrenPolicy
+}
+
+// newChildrenLimit creates a new childrenLimit
+func newChildrenLimit(maxChildren int, policy MaxChildrenPolicy) *childrenLimit {
+	return &childrenLimit{
+		maxChildren: maxChildren,
+		policy:      policy,
+	}
+}
+
+// isReached returns true when the given children count does not allow a new
+// child to be spawned
+func (cl *childrenLimit) isReached(childrenCount int) bool {
+	return cl != nil && childrenCount >= cl.maxChildren
+}
@@ -0,0 +1,184 @@
+package s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestMaxChildrenRejectSpawn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	dyn, err := cap.NewDynSupervisor(
+		ctx, "root", cap.WithMaxChildren(2, cap.RejectSpawn),
+	)
+	assert.NoError(t, err)
+
+	cancelChild1, err := dyn.Spawn(WaitDoneWorker("child1"))
+	assert.NoError(t, err)
+	_, err = dyn.Spawn(WaitDoneWorker("child2"))
+	assert.NoError(t, err)
+
+	_, err = dyn.Spawn(WaitDoneWorker("child3"))
+	assert.True(t, errors.Is(err, cap.ErrMaxChildrenReached))
+	var maxErr *cap.MaxChildrenReachedError
+	assert.True(t, errors.As(err, &maxErr))
+	assert.Equal(
+		t,
+		"supervisor 'root' cannot spawn more than 2 children",
+		cap.ExplainError(err),
+	)
+
+	// terminating a child frees a slot
+	assert.NoError(t, cancelChild1())
+	_, err = dyn.Spawn(WaitDoneWorker("child3"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, dyn.Terminate())
+}
+
+func TestMaxChildrenWaitForSlotDeadline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	dyn, err := cap.NewDynSupervisor(
+		ctx, "root", cap.WithMaxChildren(1, cap.WaitForSlot),
+	)
+	assert.NoError(t, err)
+
+	_, err = dyn.Spawn(WaitDoneWorker("child1"))
+	assert.NoError(t, err)
+
+	spawnCtx, spawnCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer spawnCancel()
+
+	_, err = dyn.SpawnContext(spawnCtx, WaitDoneWorker("child2"))
+	assert.True(t, errors.Is(err, cap.ErrMaxChildrenReached))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	assert.NoError(t, dyn.Terminate())
+}
+
+func TestMaxChildrenWaitForSlot(t *testing.T) {
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	dyn, err := cap.NewDynSupervisor(
+		ctx,
+		"root",
+		cap.WithMaxChildren(1, cap.WaitForSlot),
+		cap.WithNotifier(evManager.EventCollector(ctx)),
+	)
+	assert.NoError(t, err)
+
+	child1, completeWorker1 := CompleteOnSignalWorker(
+		1, "child1", cap.WithRestart(cap.Temporary),
+	)
+	_, err = dyn.Spawn(child1)
+	assert.NoError(t, err)
+
+	spawnErrCh := make(chan error, 1)
+	go func() {
+		spawnCtx, spawnCancel := context.WithTimeout(ctx, 5*time.Second)
+		defer spawnCancel()
+		_, err := dyn.SpawnContext(spawnCtx, WaitDoneWorker("child2"))
+		spawnErrCh <- err
+	}()
+
+	// the second spawn is blocked until the first child finishes
+	select {
+	case <-spawnErrCh:
+		t.Fatal("spawn was expected to block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	completeWorker1()
+	assert.NoError(t, <-spawnErrCh)
+
+	assert.NoError(t, dyn.Terminate())
+	evIt := evManager.Iterator()
+	evIt.SkipTill(SupervisorTerminated("root"))
+
+	AssertExactMatch(t, evManager.Snapshot(),
+		[]EventP{
+			SupervisorStarted("root"),
+			WorkerStarted("root/child1"),
+			WorkerCompleted("root/child1"),
+			WorkerStarted("root/child2"),
+			WorkerTerminated("root/child2"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestMaxChildrenWaitForSlotTerminated(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	dyn, err := cap.NewDynSupervisor(
+		ctx, "root", cap.WithMaxChildren(1, cap.WaitForSlot),
+	)
+	assert.NoError(t, err)
+
+	_, err = dyn.Spawn(WaitDoneWorker("child1"))
+	assert.NoError(t, err)
+
+	spawnErrCh := make(chan error, 1)
+	go func() {
+		_, err := dyn.SpawnContext(context.TODO(), WaitDoneWorker("child2"))
+		spawnErrCh <- err
+	}()
+
+	// give the spawn call time to block
+	time.Sleep(50 * time.Millisecond)
+
+	// waiting clients are released when the supervisor terminates
+	assert.NoError(t, dyn.Terminate())
+	assert.Error(t, <-spawnErrCh)
+}
+
+func TestMaxChildrenDynSubtreeContextSpawner(t *testing.T) {
+	spawnErrCh := make(chan error, 1)
+	subtree := cap.NewDynSubtreeWithNotifyStart(
+		"dyn",
+		func(ctx context.Context, notifyStart cap.NotifyStartFn, spawner cap.Spawner) error {
+			_, err := spawner.Spawn(WaitDoneWorker("child1"))
+			assert.NoError(t, err)
+
+			cs, ok := spawner.(cap.ContextSpawner)
+			assert.True(t, ok)
+
+			spawnCtx, spawnCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+			defer spawnCancel()
+			_, err = cs.SpawnContext(spawnCtx, WaitDoneWorker("child2"))
+			spawnErrCh <- err
+
+			notifyStart(nil)
+			<-ctx.Done()
+			return nil
+		},
+		[]cap.Opt{cap.WithMaxChildren(1, cap.WaitForSlot)},
+	)
+
+	_, errs := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(subtree),
+		[]cap.Opt{},
+		func(EventManager) {},
+	)
+	assert.Empty(t, errs)
+
+	err := <-spawnErrCh
+	assert.True(t, errors.Is(err, cap.ErrMaxChildrenReached))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -2,6 +2,7 @@ package s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -30,14 +31,22 @@ func getRestartStrategy(supSpec SupervisorSpec) strategyRestartFn {
 		return oneForOneRestart
 	case OneForAll:
 		return oneForAllRestart
+	case RestForOne:
+		return restForOneRestart
 	default:
 		panic("unknown restart strategy, check getRestartStrategy implementation")
 	}
 }
 
+// execRestartLoop executes the restart strategy of the supervisor until the
+// source child gets restarted or the restart tolerance is surpassed. When a
+// restartDelay is given, the restart strategy is not executed right away, but
+// scheduled on the supScheduler so that the monitor loop can continue handling
+// other messages.
 func execRestartLoop(
 	supCtx context.Context,
 	supTolerance *restartToleranceManager,
+	supScheduler *restartScheduler,
 	supSpec SupervisorSpec,
 	supChildrenSpecs []c.ChildSpec,
 	supRuntimeName string,
@@ -45,7 +54,10 @@ func execRestartLoop(
 	supNotifyChan chan c.ChildNotification,
 	sourceCh c.Child,
 	sourceErr error,
-) (map[string]c.Child, *RestartToleranceReached) {
+	restartDelay time.Duration,
+) (map[string]c.Child, restartGiveUpError) {
+	eventNotifier := supSpec.getEventNotifier()
+	supEventNotifier := eventNotifier.withLabels(supSpec.getLabels())
 	execRestart := getRestartStrategy(supSpec)
 	var prevErr, restartErr error
 
@@ -54,10 +66,17 @@ func execRestartLoop(
 	// on
 	prevErr = sourceErr
 
+	// prevChildren is used to carry over the restart bookkeeping of the children
+	// that get restarted (restart strategies may modify the supChildren map)
+	prevChildren := make(map[string]c.Child, len(supChildren))
+	for chName, ch := range supChildren {
+		prevChildren[chName] = ch
+	}
+
 	for {
 		if prevErr != nil {
-			ok := supTolerance.checkToleranceExceeded(prevErr)
-			if !ok {
+			check := supTolerance.checkToleranceExceeded(prevErr)
+			if check == restartToleranceSurpassed {
 				// Very important! even though we return an error value
 				// here, we want to return a supChildren, this collection
 				// gets replaced on every iteration, and if we return a nil
@@ -69,8 +88,25 @@ func execRestartLoop(
 					prevErr,
 				)
 			}
+			supEventNotifier.restartToleranceUpdated(
+				c.Supervisor, supRuntimeName, sourceCh.GetRuntimeName(), check, supTolerance,
+			)
+		}
+
+		if restartDelay > 0 {
+			// we already accounted the source error on the restart tolerance, the
+			// restart is going to happen once the delay is done
+			supScheduler.schedule(sourceCh, restartDelay)
+			eventNotifier.withLabels(sourceCh.GetSpec().GetLabels()).childRestartScheduled(
+				sourceCh, restartDelay,
+			)
+			return supChildren, nil
 		}
 
+		supEventNotifier.supervisorRestarting(
+			supRuntimeName, sourceCh.GetRuntimeName(), supSpec.strategy, supTolerance,
+		)
+
 		supChildren, restartErr = execRestart(
 			supCtx,
 			supSpec, supChildrenSpecs,
@@ -79,6 +115,14 @@ func execRestartLoop(
 		)
 
 		if restartErr == nil {
+			// the new runtime of restarted children keep the restart bookkeeping of
+			// their previous runtime
+			for chName, newCh := range supChildren {
+				prevCh, ok := prevChildren[chName]
+				if ok && !newCh.GetCreatedAt().Equal(prevCh.GetCreatedAt()) {
+					supChildren[chName] = newCh.WithRestartStateOf(prevCh)
+				}
+			}
 			return supChildren, nil
 		}
 
@@ -89,6 +133,7 @@ func execRestartLoop(
 func handleChildNodeError(
 	supCtx context.Context,
 	supTolerance *restartToleranceManager,
+	supScheduler *restartScheduler,
 	supSpec SupervisorSpec, supChildrenSpecs []c.ChildSpec,
 
 	supRuntimeName string,
@@ -96,34 +141,84 @@ func handleChildNodeError(
 	supNotifyChan chan c.ChildNotification,
 
 	sourceCh c.Child, sourceErr error,
-) (map[string]c.Child, *RestartToleranceReached) {
-	eventNotifier := supSpec.getEventNotifier()
+) ([]c.ChildSpec, map[string]c.Child, restartGiveUpError) {
 	chSpec := sourceCh.GetSpec()
+	eventNotifier := supSpec.getEventNotifier().withLabels(chSpec.GetLabels())
 
 	eventNotifier.processFailed(chSpec.GetTag(), sourceCh.GetRuntimeName(), sourceErr)
+	// a hung child that failed its liveness check may have been abandoned
+	notifyAbandoned(eventNotifier, supSpec.inbox, sourceCh, sourceErr)
+
+	// a draining supervisor does not restart children
+	if supSpec.dynChildren.isDraining() {
+		delete(supChildren, chSpec.GetName())
+		return supChildrenSpecs, supChildren, nil
+	}
+
+	// panics of children with the EscalateOnPanic policy make the supervisor
+	// fail right away, restarting them is not safe
+	var panicErr *c.PanicError
+	if chSpec.GetPanicPolicy() == c.EscalateOnPanic && errors.As(sourceErr, &panicErr) {
+		delete(supChildren, chSpec.GetName())
+		return supChildrenSpecs, supChildren, newPanicEscalatedError(sourceCh, sourceErr)
+	}
 
 	switch chSpec.GetRestart() {
 	case c.Permanent, c.Transient:
+		// Errors are accounted on the child's own restart tolerance first, when
+		// this tolerance is surpassed, the supervisor restart tolerance is not
+		// modified.
+		var chToleranceErr *RestartToleranceReached
+		sourceCh, chToleranceErr = checkChildToleranceExceeded(
+			eventNotifier, sourceCh, sourceErr,
+		)
+
+		if chToleranceErr != nil {
+			eventNotifier.childRestartToleranceReached(
+				chSpec.GetTag(), sourceCh.GetRuntimeName(), chToleranceErr,
+			)
+			if chSpec.GetToleranceAction() == c.Escalate {
+				return supChildrenSpecs, supChildren, chToleranceErr
+			}
+			// c.GiveUp: the supervisor will not restart this child again, we
+			// remove its spec so that the restart of a sibling does not start it
+			delete(supChildren, chSpec.GetName())
+			supChildrenSpecs = removeFinishedChildSpec(
+				supChildrenSpecs, supChildren, chSpec.GetName(),
+			)
+			return supChildrenSpecs, supChildren, nil
+		}
+
+		// we keep the updated tolerance and backoff state in case the child gets
+		// restarted by a sibling while its restart is delayed
+		sourceCh = sourceCh.WithTerminationHandled()
+		supChildren[chSpec.GetName()] = sourceCh
+
 		// On error scenarios, Permanent and Transient try as much as possible
 		// to restart the failing child
-		return execRestartLoop(
+		var restartErr restartGiveUpError
+		supChildren, restartErr = execRestartLoop(
 			supCtx,
 			supTolerance,
+			supScheduler,
 			supSpec, supChildrenSpecs,
 			supRuntimeName, supChildren, supNotifyChan,
 			sourceCh, sourceErr,
+			sourceCh.GetRestartDelay(),
 		)
+		return supChildrenSpecs, supChildren, restartErr
 
 	default: /* Temporary */
 		// Temporary children can complete or fail, supervisor will not restart them
 		delete(supChildren, chSpec.GetName())
-		return supChildren, nil
+		return supChildrenSpecs, supChildren, nil
 	}
 }
 
 func handleChildNodeCompletion(
 	supCtx context.Context,
 	supTolerance *restartToleranceManager,
+	supScheduler *restartScheduler,
 	supSpec SupervisorSpec, supChildSpecs []c.ChildSpec,
 
 	supRuntimeName string,
@@ -131,15 +226,22 @@ func handleChildNodeCompletion(
 	supNotifyChan chan c.ChildNotification,
 
 	sourceCh c.Child,
-) (map[string]c.Child, *RestartToleranceReached) {
-	eventNotifier := supSpec.getEventNotifier()
+) (map[string]c.Child, restartGiveUpError) {
+	chSpec := sourceCh.GetSpec()
+	eventNotifier := supSpec.getEventNotifier().withLabels(chSpec.GetLabels())
+
+	// a draining supervisor does not restart children, they are finishing on
+	// their own
+	if supSpec.dynChildren.isDraining() {
+		eventNotifier.processDrained(chSpec.GetTag(), sourceCh.GetRuntimeName())
+		delete(supChildren, chSpec.GetName())
+		return supChildren, nil
+	}
 
 	if sourceCh.IsWorker() {
 		eventNotifier.workerCompleted(sourceCh.GetRuntimeName())
 	}
 
-	chSpec := sourceCh.GetSpec()
-
 	switch chSpec.GetRestart() {
 
 	case c.Transient, c.Temporary:
@@ -147,15 +249,20 @@ func handleChildNodeCompletion(
 		// Do nothing
 		return supChildren, nil
 	default: /* Permanent */
+		sourceCh = sourceCh.WithTerminationHandled()
+		supChildren[chSpec.GetName()] = sourceCh
+
 		// On child completion, the supervisor still restart the child when the
 		// c.Restart is Permanent
 		return execRestartLoop(
 			supCtx,
 			supTolerance,
+			supScheduler,
 			supSpec, supChildSpecs,
 			supRuntimeName, supChildren, supNotifyChan,
 			sourceCh,
 			nil, /* error */
+			sourceCh.GetRestartDelay(),
 		)
 	}
 }
@@ -163,6 +270,7 @@ func handleChildNodeCompletion(
 func handleChildNodeNotification(
 	supCtx context.Context,
 	supTolerance *restartToleranceManager,
+	supScheduler *restartScheduler,
 	supSpec SupervisorSpec,
 	supChildSpecs []c.ChildSpec,
 	supRuntimeName string,
@@ -170,7 +278,7 @@ func handleChildNodeNotification(
 	supNotifyChan chan c.ChildNotification,
 	sourceCh c.Child,
 	chNotification c.ChildNotification,
-) (map[string]c.Child, *RestartToleranceReached) {
+) ([]c.ChildSpec, map[string]c.Child, restartGiveUpError) {
 	sourceErr := chNotification.Unwrap()
 
 	if sourceErr != nil {
@@ -179,6 +287,7 @@ func handleChildNodeNotification(
 		return handleChildNodeError(
 			supCtx,
 			supTolerance,
+			supScheduler,
 			supSpec, supChildSpecs,
 
 			supRuntimeName, supChildren, supNotifyChan,
@@ -187,12 +296,68 @@ func handleChildNodeNotification(
 		)
 	}
 
-	return handleChildNodeCompletion(
+	supChildren, restartErr := handleChildNodeCompletion(
+		supCtx,
+		supTolerance,
+		supScheduler,
+		supSpec, supChildSpecs,
+		supRuntimeName, supChildren, supNotifyChan,
+		sourceCh,
+	)
+	return supChildSpecs, supChildren, restartErr
+}
+
+// removeFinishedChildSpec removes the spec of the child with the given name
+// when the child is not part of the supervisor children anymore (e.g. a
+// Temporary child that finished). This function is used on supervisors with
+// dynamic children and on children the supervisor gave up on, to avoid
+// restarting a finished child when the supervisor restarts its siblings, and to
+// allow clients to re-use the child name.
+func removeFinishedChildSpec(
+	supChildrenSpecs []c.ChildSpec,
+	supChildren map[string]c.Child,
+	chName string,
+) []c.ChildSpec {
+	if _, ok := supChildren[chName]; ok {
+		return supChildrenSpecs
+	}
+	newChildrenSpecs := make([]c.ChildSpec, 0, len(supChildrenSpecs))
+	for _, chSpec := range supChildrenSpecs {
+		if chSpec.GetName() != chName {
+			newChildrenSpecs = append(newChildrenSpecs, chSpec)
+		}
+	}
+	return newChildrenSpecs
+}
+
+// handleDelayedRestart executes the restart of a child node which restart was
+// delayed by a RestartBackoff setting.
+func handleDelayedRestart(
+	supCtx context.Context,
+	supTolerance *restartToleranceManager,
+	supScheduler *restartScheduler,
+	supSpec SupervisorSpec,
+	supChildSpecs []c.ChildSpec,
+	supRuntimeName string,
+	supChildren map[string]c.Child,
+	supNotifyChan chan c.ChildNotification,
+	sourceCh c.Child,
+) (map[string]c.Child, restartGiveUpError) {
+	// the child may have been restarted or removed while the restart was
+	// pending, if that is the case, there is nothing to do
+	if !supScheduler.isCurrent(supChildren, sourceCh) {
+		return supChildren, nil
+	}
+
+	return execRestartLoop(
 		supCtx,
 		supTolerance,
+		supScheduler,
 		supSpec, supChildSpecs,
 		supRuntimeName, supChildren, supNotifyChan,
 		sourceCh,
+		nil, /* error, it was accounted when the restart got scheduled */
+		0,   /* restart delay */
 	)
 }
 
@@ -219,48 +384,56 @@ func skipChild(ch c.Child) skipChildFn {
 	}
 }
 
-// TODO: use functions bellow for RestForOne strategy
-//
+// skipChildNotIn is a skipChildFn that skips all the child entries from an
+// iteration that are not present in the given set of child names.
+func skipChildNotIn(chNames map[string]struct{}) skipChildFn {
+	return func(_ int, chSpec c.ChildSpec) bool {
+		_, ok := chNames[chSpec.GetName()]
+		return !ok
+	}
+}
 
-// func skipChildBeforeIndex(i int) skipChildFn {
-//	return func(j int, _ c.ChildSpec) bool {
-//		return i < j
-//	}
-// }
-
-// func getChildIndex(chs []c.ChildSpec, ch c.Child) (int, bool) {
-//	chSpec := ch.GetSpec()
-//	for i, other := range chs {
-//		if chSpec.GetName() == other.GetName() {
-//			return i, true
-//		}
-//	}
-//	return 0, false
-// }
+// getChildIndex returns the position of the given child on the given children
+// spec list.
+func getChildIndex(chs []c.ChildSpec, ch c.Child) (int, bool) {
+	chSpec := ch.GetSpec()
+	for i, other := range chs {
+		if chSpec.GetName() == other.GetName() {
+			return i, true
+		}
+	}
+	return 0, false
+}
 
 ////////////////////////////////////////////////////////////////////////////////
 
+// buildChildRuntimeName creates the runtime name of a child node from the
+// supervisor runtime name and the child spec name
+func buildChildRuntimeName(supRuntimeName string, chSpec c.ChildSpec) string {
+	return strings.Join([]string{supRuntimeName, chSpec.GetName()}, NodeSepToken)
+}
+
 // startChildNode is responsible of starting a single child. This function will
 // deal with the child lifecycle notification. It will return an error if
-// something goes wrong with the initialization of this child.
+// something goes wrong with the initialization of this child. When
+// readinessTimeout is greater than zero, this function blocks until the child
+// is ready (see WithReadiness) or until the timeout is done.
 func startChildNode(
 	startCtx context.Context,
 	supSpec SupervisorSpec,
 	supRuntimeName string,
 	notifyCh chan c.ChildNotification,
 	chSpec c.ChildSpec,
+	readinessTimeout time.Duration,
 ) (c.Child, error) {
-	eventNotifier := supSpec.getEventNotifier()
+	eventNotifier := supSpec.getEventNotifier().withLabels(chSpec.GetLabels())
 	startedTime := time.Now()
 	ch, chStartErr := chSpec.DoStart(startCtx, supRuntimeName, notifyCh)
 
 	// NOTE: The error handling code bellow gets executed when the children
 	// fails at start time
 	if chStartErr != nil {
-		cRuntimeName := strings.Join(
-			[]string{supRuntimeName, chSpec.GetName()},
-			NodeSepToken,
-		)
+		cRuntimeName := buildChildRuntimeName(supRuntimeName, chSpec)
 		eventNotifier.processStartFailed(chSpec.GetTag(), cRuntimeName, chStartErr)
 		return c.Child{}, chStartErr
 	}
@@ -268,29 +441,46 @@ func startChildNode(
 	// NOTE: we only notify when child is a worker because sub-trees supervisors
 	// are responsible of their own notification
 	if chSpec.IsWorker() {
-		eventNotifier.workerStarted(ch.GetRuntimeName(), startedTime)
+		notifyWorkerStarted(
+			startCtx,
+			eventNotifier,
+			supSpec.inbox,
+			ch,
+			startedTime,
+			readinessTimeout,
+		)
 	}
 	return ch, nil
 }
 
-// TODO: introduce shouldSkip when supporting RestForOne functionality
-
 // startChildNodes iterates over all the children (specified with `cap.WithNodes`
 // and `cap.WithSubtree`) starting a goroutine for each. The children iteration
 // will be sorted as specified with the `cap.WithStartOrder` option. In case any child
 // fails to start, the supervisor start operation will be aborted and all the
 // started children so far will be stopped in the reverse order.
+//
+// The shouldSkip function allows restart strategies to only start a subset of
+// the supervisor children; the returned map only contains the children started
+// by this call. When readinessTimeout is greater than zero, each child is
+// started once the previous child is ready; restart strategies run in the
+// monitor loop, so they never wait for readiness.
 func startChildNodes(
 	startCtx context.Context,
 	supSpec SupervisorSpec,
 	supChildrenSpecs []c.ChildSpec,
 	supRuntimeName string,
 	notifyCh chan c.ChildNotification,
+	shouldSkip skipChildFn,
+	readinessTimeout time.Duration,
 ) (map[string]c.Child, error) {
 	children := make(map[string]c.Child)
 
 	// Start children in the correct order
-	for _, chSpec := range supSpec.order.sortStart(supChildrenSpecs) {
+	for i, chSpec := range supSpec.order.sortStart(supChildrenSpecs) {
+		if shouldSkip(i, chSpec) {
+			continue
+		}
+
 		// the function above will modify the children internally
 		ch, chStartErr := startChildNode(
 			startCtx,
@@ -298,6 +488,10 @@ func startChildNodes(
 			supRuntimeName,
 			notifyCh,
 			chSpec,
+			// the next child is started once this child is ready (or once the
+			// readiness timeout is done); a child that finishes before it is ready
+			// gets restarted by the monitor loop
+			readinessTimeout,
 		)
 		if chStartErr != nil {
 			// we must stop previously started children before we finish the supervisor
@@ -306,6 +500,7 @@ func startChildNodes(
 				supChildrenSpecs,
 				children,
 				noChildSkip,
+				time.Time{},
 			)
 			var terminationErr *SupervisorTerminationError
 			if len(nodeErrMap) > 0 {
@@ -333,11 +528,29 @@ func startChildNodes(
 // an error on termination it notifies the event system
 func terminateChildNode(
 	eventNotifier EventNotifier,
+	inbox *monitorInbox,
+	ch c.Child,
+) error {
+	return terminateChildNodeWith(
+		EventNotifier.processTerminated, eventNotifier, inbox, ch, time.Time{},
+	)
+}
+
+// terminateChildNodeWith terminates the given child, and it reports the
+// termination of the child with the given notifyTerminated function. When the
+// given deadline is not zero, the child gets a shutdown timeout if it does not
+// stop before the deadline.
+func terminateChildNodeWith(
+	notifyTerminated func(EventNotifier, c.ChildTag, string, time.Time),
+	supEventNotifier EventNotifier,
+	inbox *monitorInbox,
 	ch c.Child,
+	deadline time.Time,
 ) error {
 	chSpec := ch.GetSpec()
+	eventNotifier := supEventNotifier.withLabels(chSpec.GetLabels())
 	stoppingTime := time.Now()
-	isFirstTermination, terminationErr := ch.Terminate()
+	isFirstTermination, terminationErr := ch.TerminateWithin(deadline)
 
 	// if it is not the first termination (it was terminated before, or finished because
 	// of a failure), we have already made notice of this termination before, so we are
@@ -351,25 +564,91 @@ func terminateChildNode(
 		eventNotifier.processFailed(
 			chSpec.GetTag(), ch.GetRuntimeName(), terminationErr,
 		)
+		notifyAbandoned(eventNotifier, inbox, ch, terminationErr)
 		return terminationErr
 	}
 	// we need to notify that the process stopped
-	eventNotifier.processTerminated(chSpec.GetTag(), ch.GetRuntimeName(), stoppingTime)
+	notifyTerminated(eventNotifier, chSpec.GetTag(), ch.GetRuntimeName(), stoppingTime)
 	return nil
 }
 
+// notifyAbandoned reports a ProcessAbandoned event when the given termination
+// error indicates the child did not stop on time. When the goroutine of the
+// abandoned child finishes later on, a ProcessAbandonedExited event is reported
+// from the monitor loop through the given inbox; the event is dropped if the
+// supervisor is done by then.
+func notifyAbandoned(
+	eventNotifier EventNotifier,
+	inbox *monitorInbox,
+	ch c.Child,
+	terminationErr error,
+) {
+	var timeoutErr *c.ShutdownTimeoutError
+	if !errors.As(terminationErr, &timeoutErr) {
+		return
+	}
+	abandonTime := time.Now()
+	eventNotifier.processAbandoned(ch.GetTag(), ch.GetRuntimeName())
+	go func() {
+		select {
+		case <-ch.Done():
+			inbox.send(abandonedExitedMsg{
+				tag:         ch.GetTag(),
+				runtimeName: ch.GetRuntimeName(),
+				labels:      ch.GetSpec().GetLabels(),
+				abandonedAt: abandonTime,
+			})
+		case <-inbox.doneCh:
+		}
+	}()
+}
+
+// abandonedExitedMsg is a message sent to the monitor loop when the goroutine
+// of an abandoned child finishes.
+type abandonedExitedMsg struct {
+	tag         c.ChildTag
+	runtimeName string
+	labels      map[string]string
+	abandonedAt time.Time
+}
+
+func (msg abandonedExitedMsg) processMsg(
+	_ context.Context,
+	evNotifier EventNotifier,
+	_ SupervisorSpec,
+	specChildren []c.ChildSpec,
+	_ string,
+	supChildren map[string]c.Child,
+	_ chan c.ChildNotification,
+	_ *restartScheduler,
+) ([]c.ChildSpec, map[string]c.Child) {
+	// REMEMBER: WE ARE RUNNING THIS CODE IN THE SUPERVISOR THREAD
+	evNotifier.
+		withLabels(msg.labels).
+		processAbandonedExited(msg.tag, msg.runtimeName, msg.abandonedAt)
+	return specChildren, supChildren
+}
+
 // terminateChildNodes is used on the shutdown of the supervisor tree, it stops
-// children in the desired order.
+// children in the desired order. When the given deadline is not zero, children
+// that do not stop before the deadline get a shutdown timeout.
 func terminateChildNodes(
 	supSpec SupervisorSpec,
 	supChildrenSpecs0 []c.ChildSpec,
 	supChildren map[string]c.Child,
 	shouldSkip skipChildFn,
+	deadline time.Time,
 ) map[string]error {
 	eventNotifier := supSpec.eventNotifier
 	supChildrenSpecs := supSpec.order.sortTermination(supChildrenSpecs0)
 	supNodeErrMap := make(map[string]error)
 
+	// children that did not finish during a drain are force-terminated
+	notifyTerminated := EventNotifier.processTerminated
+	if supSpec.dynChildren.isDraining() {
+		notifyTerminated = EventNotifier.processForceTerminated
+	}
+
 	for i, chSpec := range supChildrenSpecs {
 		if shouldSkip(i, chSpec) {
 			continue
@@ -386,7 +665,9 @@ func terminateChildNodes(
 		// * On stop, there may be a Transient child that completed, or a Temporary child
 		// that completed or failed.
 		if ok {
-			terminationErr := terminateChildNode(eventNotifier, ch)
+			terminationErr := terminateChildNodeWith(
+				notifyTerminated, eventNotifier, supSpec.inbox, ch, deadline,
+			)
 			if terminationErr != nil {
 				// if a child fails to stop (either because of a legit failure or a
 				// timeout), we store the terminationError so that we can report all of them
@@ -407,14 +688,17 @@ func terminateSupervisor(
 	supRscCleanup CleanupResourcesFn,
 	supChildren map[string]c.Child,
 	onTerminate func(error),
-	restartErr *RestartToleranceReached,
+	restartErr restartGiveUpError,
 ) error {
 	var terminateErr *SupervisorTerminationError
+	// the shutdown of the supervisor bounds the time we wait for all its
+	// children to stop
 	supNodeErrMap := terminateChildNodes(
 		supSpec,
 		supChildrenSpecs,
 		supChildren,
 		noChildSkip,
+		supSpec.shutdown.Deadline(time.Now()),
 	)
 	supRscCleanupErr := supRscCleanup()
 
@@ -493,7 +777,13 @@ func runMonitorLoop(
 	onTerminate notifyTerminationFn,
 ) error {
 	var startErr error
-	var restartErr *RestartToleranceReached
+	var restartErr restartGiveUpError
+
+	// events of goroutines spawned by the supervisor (e.g. readiness of
+	// workers) are reported from the monitor loop, this includes the workers
+	// started below
+	supSpec.inbox = newMonitorInbox()
+	defer supSpec.inbox.stop()
 
 	// Start children
 	supChildren, startErr := startChildNodes(
@@ -502,6 +792,8 @@ func runMonitorLoop(
 		supChildrenSpecs,
 		supRuntimeName,
 		supNotifyChan,
+		noChildSkip,
+		supSpec.readinessTimeout,
 	)
 	if startErr != nil {
 		// in case we run in the async strategy we notify the spawner that we
@@ -515,14 +807,24 @@ func runMonitorLoop(
 	// started (we would get race-conditions if we notify from the parent
 	// otherwise).
 	eventNotifier := supSpec.getEventNotifier()
-	eventNotifier.supervisorStarted(supRuntimeName, supStartTime)
+	eventNotifier.withLabels(supSpec.getLabels()).supervisorStarted(supRuntimeName, supStartTime)
 
 	/// Once children have been spawned, we notify to the caller thread that the
 	// main loop has started without errors.
 	onStart(nil)
 
+	// supScheduler keeps track of child restarts that are delayed
+	supScheduler := newRestartScheduler()
+	defer supScheduler.stop()
+
+	// wake up clients waiting for a child removal, they will find out the
+	// supervisor is terminated
+	defer supSpec.dynChildren.notifyRemoval()
+
 	// Supervisor Loop
 	for {
+		childrenCount := len(supChildren)
+
 		select {
 		// parent context is done
 		case <-supCtx.Done():
@@ -551,14 +853,43 @@ func runMonitorLoop(
 				)
 			}
 
-			supChildren, restartErr = handleChildNodeNotification(
+			supChildrenSpecs, supChildren, restartErr = handleChildNodeNotification(
 				supCtx,
 				supTolerance,
+				supScheduler,
 				supSpec, supChildrenSpecs,
 				supRuntimeName, supChildren, supNotifyChan,
 				sourceCh, chNotification,
 			)
 
+			if supSpec.dynChildren != nil {
+				supChildrenSpecs = removeFinishedChildSpec(
+					supChildrenSpecs, supChildren, sourceCh.GetName(),
+				)
+			}
+
+			if restartErr != nil {
+				return terminateSupervisor(
+					supSpec,
+					supChildrenSpecs,
+					supRuntimeName,
+					supRscCleanup,
+					supChildren,
+					onTerminate,
+					restartErr,
+				)
+			}
+
+		case sourceCh := <-supScheduler.restartCh:
+			supChildren, restartErr = handleDelayedRestart(
+				supCtx,
+				supTolerance,
+				supScheduler,
+				supSpec, supChildrenSpecs,
+				supRuntimeName, supChildren, supNotifyChan,
+				sourceCh,
+			)
+
 			if restartErr != nil {
 				return terminateSupervisor(
 					supSpec,
@@ -571,6 +902,19 @@ func runMonitorLoop(
 				)
 			}
 
+		case msg := <-supSpec.inbox.msgCh:
+			supChildrenSpecs, supChildren = handleCtrlMsg(
+				supCtx,
+				eventNotifier,
+				supSpec,
+				supChildrenSpecs,
+				supRuntimeName,
+				supChildren,
+				supNotifyChan,
+				supScheduler,
+				msg,
+			)
+
 		case msg := <-ctrlChan:
 			supChildrenSpecs, supChildren = handleCtrlMsg(
 				supCtx,
@@ -580,8 +924,14 @@ func runMonitorLoop(
 				supRuntimeName,
 				supChildren,
 				supNotifyChan,
+				supScheduler,
 				msg,
 			)
 		}
+
+		// a child was removed, clients waiting to spawn a child may try again
+		if len(supChildren) < childrenCount {
+			supSpec.dynChildren.notifyRemoval()
+		}
 	}
 }
@@ -0,0 +1,33 @@
+package s
+
+// monitorInbox delivers messages from goroutines spawned by a supervisor (e.g.
+// workers waiting to be ready, or abandoned workers) to the supervisor monitor
+// loop, so that their events are reported from the supervisor goroutine.
+// Messages sent after the monitor loop is done are dropped.
+type monitorInbox struct {
+	msgCh  chan ctrlMsg
+	doneCh chan struct{}
+}
+
+// newMonitorInbox creates a new monitorInbox
+func newMonitorInbox() *monitorInbox {
+	return &monitorInbox{
+		msgCh:  make(chan ctrlMsg),
+		doneCh: make(chan struct{}),
+	}
+}
+
+// send delivers the given message to the monitor loop, it blocks until the
+// monitor loop receives the message or until the monitor loop is done.
+func (mi *monitorInbox) send(msg ctrlMsg) {
+	select {
+	case mi.msgCh <- msg:
+	case <-mi.doneCh:
+	}
+}
+
+// stop releases the goroutines sending messages to this inbox, this function
+// must be called when the monitor loop is done.
+func (mi *monitorInbox) stop() {
+	close(mi.doneCh)
+}
@@ -0,0 +1,61 @@
+package s_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+type nodeContextKey struct{}
+
+func TestWithNodeContext(t *testing.T) {
+	var mux sync.Mutex
+	var started []string
+
+	// every node gets the runtime names of its ancestors on its context
+	nodeContextFn := func(ctx context.Context, name string, tag cap.NodeTag) context.Context {
+		mux.Lock()
+		defer mux.Unlock()
+		started = append(started, tag.String()+":"+name)
+		parents, _ := ctx.Value(nodeContextKey{}).([]string)
+		return context.WithValue(ctx, nodeContextKey{}, append(parents[:len(parents):len(parents)], name))
+	}
+
+	valueCh := make(chan []string, 1)
+	child1 := cap.NewWorker("child1", func(ctx context.Context) error {
+		valueCh <- ctx.Value(nodeContextKey{}).([]string)
+		<-ctx.Done()
+		return nil
+	})
+
+	subtree1 := cap.NewSupervisorSpec("subtree1", cap.WithNodes(child1))
+
+	_, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(cap.Subtree(subtree1), WaitDoneWorker("child2")),
+		[]cap.Opt{cap.WithNodeContext(nodeContextFn)},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+		},
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"root", "root/subtree1", "root/subtree1/child1"}, <-valueCh)
+	assert.Equal(
+		t,
+		[]string{
+			"Supervisor:root",
+			"Supervisor:root/subtree1",
+			"Worker:root/subtree1/child1",
+			"Worker:root/child2",
+		},
+		started,
+	)
+}
@@ -2,6 +2,8 @@ package s
 
 import (
 	"context"
+	"time"
+
 	"github.com/capatazlib/go-capataz/internal/c"
 )
 
@@ -19,7 +21,7 @@ var oneForAllRestart strategyRestartFn = func(
 	// nonetheless, this error is not going unnoticed given the event
 	// notifier gets called on child termination.
 	_ /* nodeErrMap */ = terminateChildNodes(
-		spec, supChildrenSpecs, supChildren0, skipChild(sourceCh),
+		spec, supChildrenSpecs, supChildren0, skipChild(sourceCh), time.Time{},
 	)
 
 	return startChildNodes(
@@ -28,5 +30,8 @@ var oneForAllRestart strategyRestartFn = func(
 		supChildrenSpecs,
 		supRuntimeName,
 		supNotifyChan,
+		noChildSkip,
+		// restarts do not block the monitor loop on the readiness of children
+		0,
 	)
 }
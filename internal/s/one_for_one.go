@@ -17,9 +17,8 @@ var oneForOneRestart strategyRestartFn = func(
 
 	sourceCh c.Child,
 ) (map[string]c.Child, error) {
-	eventNotifier := spec.getEventNotifier()
-
 	chSpec := sourceCh.GetSpec()
+	eventNotifier := spec.getEventNotifier().withLabels(chSpec.GetLabels())
 	chName := chSpec.GetName()
 
 	startTime := time.Now()
@@ -38,7 +37,7 @@ var oneForOneRestart strategyRestartFn = func(
 	// notify event only for workers, supervisors are responsible of their
 	// own notifications
 	if newCh.GetTag() == c.Worker {
-		eventNotifier.workerStarted(newCh.GetRuntimeName(), startTime)
+		notifyWorkerStarted(supCtx, eventNotifier, spec.inbox, newCh, startTime, 0)
 	}
 	return supChildren, nil
 }
@@ -0,0 +1,177 @@
+package s_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestEscalateOnPanic(t *testing.T) {
+	panicChild1, signalPanic1 := PanicOnSignalWorker(
+		1,
+		"child1",
+		cap.WithPanicPolicy(cap.EscalateOnPanic),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(panicChild1, WaitDoneWorker("child2")),
+		// the restart tolerance would allow a restart of child1
+		[]cap.Opt{cap.WithRestartTolerance(10, time.Minute)},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+			signalPanic1(true /* done */)
+			evIt.SkipTill(WorkerTerminated("root/child2"))
+		},
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, "supervisor crashed due to an escalated panic", err.Error())
+
+	var escalatedErr *cap.PanicEscalatedError
+	assert.True(t, errors.As(err, &escalatedErr))
+	assert.Equal(t, "root/child1", escalatedErr.GetNodeName())
+
+	var panicErr *cap.PanicError
+	assert.True(t, errors.As(err, &panicErr))
+
+	// the supervisor did not give up because of its restart tolerance
+	var toleranceErr *cap.RestartToleranceReached
+	assert.False(t, errors.As(err, &toleranceErr))
+
+	kvs := err.(cap.ErrKVs).KVs()
+	assert.Equal(t, "root/child1", kvs["supervisor.restart.node.name"])
+	assert.Equal(t, "Panicking child (1 out of 1)", kvs["supervisor.restart.node.error.panic.msg"])
+
+	explanation := cap.ExplainError(err)
+	assert.True(
+		t,
+		strings.HasPrefix(
+			explanation,
+			"supervisor 'root' crashed due to an escalated panic.\n"+
+				"\tnode 'root/child1' panicked, the panic was escalated to its supervisor.\n"+
+				"\t\t> Panicking child (1 out of 1)\n",
+		),
+		explanation,
+	)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+			WorkerFailedWith("root/child1", "Panicking child (1 out of 1)"),
+			// ^^^ child1 is not restarted
+			WorkerTerminated("root/child2"),
+			SupervisorFailed("root"),
+		},
+	)
+}
+
+func TestSupervisorPanicPolicy(t *testing.T) {
+	panicChild1, signalPanic1 := PanicOnSignalWorker(1, "child1")
+	// the policy of the worker overrides the policy of the supervisor
+	panicChild2, signalPanic2 := PanicOnSignalWorker(
+		1,
+		"child2",
+		cap.WithPanicPolicy(cap.RestartOnPanic),
+	)
+
+	subtree1 := cap.NewSupervisorSpec(
+		"subtree1",
+		cap.WithNodes(panicChild1, panicChild2),
+		cap.WithSupervisorPanicPolicy(cap.EscalateOnPanic),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(cap.Subtree(subtree1)),
+		[]cap.Opt{},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			signalPanic2(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/subtree1/child2"))
+
+			signalPanic1(true /* done */)
+			evIt.SkipTill(SupervisorStarted("root/subtree1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/subtree1/child1"),
+			WorkerStarted("root/subtree1/child2"),
+			SupervisorStarted("root/subtree1"),
+			SupervisorStarted("root"),
+			WorkerFailedWith("root/subtree1/child2", "Panicking child (1 out of 1)"),
+			// ^^^ child2 is restarted, as specified by its own panic policy
+			WorkerStarted("root/subtree1/child2"),
+			WorkerFailedWith("root/subtree1/child1", "Panicking child (1 out of 1)"),
+			// ^^^ child1 panic is escalated, as specified by the subtree policy
+			WorkerTerminated("root/subtree1/child2"),
+			SupervisorFailed("root/subtree1"),
+			// ^^^ the root supervisor restarts the subtree, given it uses the
+			// default panic policy
+			WorkerStarted("root/subtree1/child1"),
+			WorkerStarted("root/subtree1/child2"),
+			SupervisorStarted("root/subtree1"),
+			WorkerTerminated("root/subtree1/child2"),
+			WorkerTerminated("root/subtree1/child1"),
+			SupervisorTerminated("root/subtree1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestSupervisorPanicPolicyInheritance(t *testing.T) {
+	panicChild1, signalPanic1 := PanicOnSignalWorker(1, "child1")
+
+	// the subtree inherits the panic policy of the root supervisor
+	subtree1 := cap.NewSupervisorSpec("subtree1", cap.WithNodes(panicChild1))
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(cap.Subtree(subtree1), WaitDoneWorker("child2")),
+		[]cap.Opt{cap.WithSupervisorPanicPolicy(cap.EscalateOnPanic)},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+			signalPanic1(true /* done */)
+			evIt.SkipTill(WorkerTerminated("root/child2"))
+		},
+	)
+
+	assert.Error(t, err)
+
+	// the panic is escalated through the whole tree
+	var panicErr *cap.PanicError
+	assert.True(t, errors.As(err, &panicErr))
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/subtree1/child1"),
+			SupervisorStarted("root/subtree1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+			WorkerFailedWith("root/subtree1/child1", "Panicking child (1 out of 1)"),
+			SupervisorFailed("root/subtree1"),
+			WorkerTerminated("root/child2"),
+			SupervisorFailed("root"),
+		},
+	)
+}
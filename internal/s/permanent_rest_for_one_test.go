@@ -0,0 +1,239 @@
+package s_test
+
+//
+// NOTE: If you feel it is counter-intuitive to have workers start before
+// supervisors in the assertions bellow, check stest/README.md
+//
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestPermanentRestForOneSingleFailingWorkerRecovers(t *testing.T) {
+	parentName := "root"
+	// Fail only one time
+	child1 := WaitDoneWorker("child1")
+	child2, failWorker2 := FailOnSignalWorker(
+		1, "child2", cap.WithRestart(cap.Permanent),
+	)
+	child3 := WaitDoneWorker("child3")
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		parentName,
+		cap.WithNodes(child1, child2, child3),
+		[]cap.Opt{cap.WithStrategy(cap.RestForOne)},
+		func(em EventManager) {
+			// NOTE: we won't stop the supervisor until the child has failed at least
+			// once
+			evIt := em.Iterator()
+			// 1) Wait till all the tree is up
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			// 2) Start the failing behavior of child2
+			failWorker2(true /* done */)
+			evIt.SkipTill(WorkerFailed("root/child2"))
+
+			// 3) Wait till first restart
+			evIt.SkipTill(WorkerStarted("root/child3"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			// start children from left to right
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/child3"),
+			SupervisorStarted("root"),
+			// ^^^ 1) failWorker2 starts executing here
+
+			WorkerFailed("root/child2"),
+			WorkerTerminated("root/child3"),
+			// ^^^ 2) child1 was started before child2, so it is not terminated
+
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/child3"),
+			// ^^^ 3) After 1st (re)start we stop
+
+			WorkerTerminated("root/child3"),
+			WorkerTerminated("root/child2"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestPermanentRestForOneRightToLeftFailingWorkerRecovers(t *testing.T) {
+	parentName := "root"
+	// Fail only one time
+	child1 := WaitDoneWorker("child1")
+	child2, failWorker2 := FailOnSignalWorker(
+		1, "child2", cap.WithRestart(cap.Permanent),
+	)
+	child3 := WaitDoneWorker("child3")
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		parentName,
+		cap.WithNodes(child1, child2, child3),
+		[]cap.Opt{
+			cap.WithStrategy(cap.RestForOne),
+			cap.WithStartOrder(cap.RightToLeft),
+		},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			// 1) Wait till all the tree is up
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			// 2) Start the failing behavior of child2
+			failWorker2(true /* done */)
+			evIt.SkipTill(WorkerFailed("root/child2"))
+
+			// 3) Wait till first restart
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			// start children from right to left
+			WorkerStarted("root/child3"),
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			// ^^^ 1) failWorker2 starts executing here
+
+			WorkerFailed("root/child2"),
+			WorkerTerminated("root/child1"),
+			// ^^^ 2) child3 was started before child2, so it is not terminated
+
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/child1"),
+			// ^^^ 3) After 1st (re)start we stop
+
+			WorkerTerminated("root/child1"),
+			WorkerTerminated("root/child2"),
+			WorkerTerminated("root/child3"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestPermanentRestForOneLastFailingWorkerRecovers(t *testing.T) {
+	parentName := "root"
+	child1 := WaitDoneWorker("child1")
+	child2 := WaitDoneWorker("child2")
+	child3, failWorker3 := FailOnSignalWorker(
+		1, "child3", cap.WithRestart(cap.Permanent),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		parentName,
+		cap.WithNodes(child1, child2, child3),
+		[]cap.Opt{cap.WithStrategy(cap.RestForOne)},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			failWorker3(true /* done */)
+			evIt.SkipTill(WorkerFailed("root/child3"))
+			evIt.SkipTill(WorkerStarted("root/child3"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/child3"),
+			SupervisorStarted("root"),
+
+			WorkerFailed("root/child3"),
+			WorkerStarted("root/child3"),
+			// ^^^ last child has no siblings after it, behaves like OneForOne
+
+			WorkerTerminated("root/child3"),
+			WorkerTerminated("root/child2"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestPermanentRestForOneSingleFailingWorkerReachThreshold(t *testing.T) {
+	parentName := "root"
+	child1 := WaitDoneWorker("child1")
+	child2, failWorker2 := FailOnSignalWorker(
+		2,
+		"child2",
+		cap.WithRestart(cap.Permanent),
+	)
+	child3 := WaitDoneWorker("child3")
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		parentName,
+		cap.WithNodes(child1, child2, child3),
+		[]cap.Opt{
+			cap.WithRestartTolerance(1, 10*time.Second),
+			cap.WithStrategy(cap.RestForOne),
+		},
+		func(em EventManager) {
+			evIt := em.Iterator()
+
+			evIt.SkipTill(SupervisorStarted("root"))
+			// ^^^ Wait till all the tree is up
+
+			failWorker2(false /* done */)
+			evIt.SkipTill(WorkerFailed("root/child2"))
+			evIt.SkipTill(WorkerStarted("root/child3"))
+			// ^^^ Wait till first restart
+
+			failWorker2(true /* done */)
+			evIt.SkipTill(WorkerFailed("root/child2"))
+			evIt.SkipTill(WorkerTerminated("root/child1"))
+			// ^^^ Wait till second failure
+		},
+	)
+
+	// This should return an error given there is no other supervisor that will
+	// rescue us when error threshold reached in a child.
+	assert.Error(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/child3"),
+			SupervisorStarted("root"),
+
+			WorkerFailed("root/child2"),
+			WorkerTerminated("root/child3"),
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/child3"),
+			// ^^^ first restart
+
+			WorkerFailed("root/child2"),
+			WorkerTerminated("root/child3"),
+			WorkerTerminated("root/child1"),
+			// ^^^ Error that indicates treshold has been met
+
+			SupervisorFailed("root"),
+		},
+	)
+}
@@ -0,0 +1,95 @@
+package s
+
+import (
+	"context"
+	"time"
+
+	"github.com/capatazlib/go-capataz/internal/c"
+)
+
+// watchReadiness spawns a goroutine that waits for the given child to be
+// ready, and sends a childReadyMsg to the monitor loop when it is.
+func watchReadiness(inbox *monitorInbox, ch c.Child) {
+	go func() {
+		// WaitReady returns false when the worker finishes before it is ready
+		if !ch.WaitReady(inbox.doneCh) {
+			return
+		}
+		inbox.send(childReadyMsg{
+			nodeName:  ch.GetName(),
+			createdAt: ch.GetCreatedAt(),
+		})
+	}()
+}
+
+// childReadyMsg is a message sent to the monitor loop when a worker reports it
+// is ready. The createdAt field identifies the runtime of the worker, so that a
+// late report of a previous runtime does not mark its successor as ready.
+type childReadyMsg struct {
+	nodeName  string
+	createdAt time.Time
+}
+
+func (msg childReadyMsg) processMsg(
+	_ context.Context,
+	evNotifier EventNotifier,
+	_ SupervisorSpec,
+	specChildren []c.ChildSpec,
+	_ string,
+	supChildren map[string]c.Child,
+	_ chan c.ChildNotification,
+	supScheduler *restartScheduler,
+) ([]c.ChildSpec, map[string]c.Child) {
+	// REMEMBER: WE ARE RUNNING THIS CODE IN THE SUPERVISOR THREAD
+
+	// the runtime that reported it is ready got restarted or removed since (or
+	// it failed and waits for a delayed restart); its successor reports its own
+	// readiness
+	ch, ok := supChildren[msg.nodeName]
+	if !ok ||
+		!ch.GetCreatedAt().Equal(msg.createdAt) ||
+		supScheduler.isPending(supChildren, msg.nodeName) {
+		return specChildren, supChildren
+	}
+
+	evNotifier.
+		withLabels(ch.GetSpec().GetLabels()).
+		processReady(c.Worker, ch.GetRuntimeName())
+	return specChildren, supChildren
+}
+
+// notifyWorkerStarted reports the ProcessStarted event of a worker child. When
+// the worker reports it is ready later on (WithReadiness option), the
+// ProcessReady event is reported once the worker is ready; if waitTimeout is
+// greater than zero, this function blocks until then (or until the timeout is
+// done), otherwise, the worker is waited on a goroutine that reports its
+// readiness to the monitor loop through the given inbox.
+func notifyWorkerStarted(
+	ctx context.Context,
+	eventNotifier EventNotifier,
+	inbox *monitorInbox,
+	ch c.Child,
+	startTime time.Time,
+	waitTimeout time.Duration,
+) {
+	if !ch.GetSpec().ReportsReadiness() {
+		eventNotifier.workerStarted(ch.GetRuntimeName(), startTime)
+		return
+	}
+
+	eventNotifier.workerStartedNotReady(ch.GetRuntimeName(), startTime)
+
+	if waitTimeout > 0 {
+		waitCtx, cancelFn := context.WithTimeout(ctx, waitTimeout)
+		defer cancelFn()
+		// we are on the supervisor goroutine, the event is reported right away
+		if ch.WaitReady(waitCtx.Done()) {
+			eventNotifier.processReady(c.Worker, ch.GetRuntimeName())
+			return
+		}
+	}
+
+	// the worker is not ready yet (or it finished, in which case the goroutine
+	// stops waiting right away)
+	watchReadiness(inbox, ch)
+}
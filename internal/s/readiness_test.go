@@ -0,0 +1,379 @@
+package s_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+// readyOnSignalWorker creates a cap.Node that reports it is ready when the
+// returned function is called
+func readyOnSignalWorker(name string) (cap.Node, func()) {
+	readyCh := make(chan struct{})
+	return cap.NewWorker(
+			name,
+			func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-readyCh:
+					cap.NotifyReady(ctx)
+				}
+				<-ctx.Done()
+				return nil
+			},
+			cap.WithReadiness(),
+		), func() {
+			close(readyCh)
+		}
+}
+
+func TestReadinessDoesNotBlockSiblings(t *testing.T) {
+	healthcheckMonitor := cap.NewHealthcheckMonitor(0, 0)
+	child1, readyChild1 := readyOnSignalWorker("child1")
+
+	// closed once the healthcheck monitor handled the ProcessReady event
+	monitorReadyCh := make(chan struct{})
+	notifier := func(ev cap.Event) {
+		healthcheckMonitor.HandleEvent(ev)
+		if ev.GetTag() == cap.ProcessReady {
+			close(monitorReadyCh)
+		}
+	}
+
+	events, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1, WaitDoneWorker("child2")),
+		[]cap.Opt{},
+		[]cap.EventNotifier{notifier},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			assert.True(t, healthcheckMonitor.IsHealthy())
+			assert.False(t, healthcheckMonitor.IsReady())
+			assert.Equal(
+				t,
+				map[string]bool{"root/child1": true},
+				healthcheckMonitor.GetHealthReport().GetNotReadyProcesses(),
+			)
+
+			readyChild1()
+			<-monitorReadyCh
+			assert.True(t, healthcheckMonitor.IsReady())
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+			WorkerReady("root/child1"),
+			WorkerTerminated("root/child2"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+
+	assert.True(t, events[0].AwaitsReadiness())
+	assert.False(t, events[1].AwaitsReadiness())
+}
+
+func TestWaitForReadiness(t *testing.T) {
+	child1 := cap.NewWorker(
+		"child1",
+		func(ctx context.Context) error {
+			// the worker warms up after it started
+			cap.NotifyReady(ctx)
+			<-ctx.Done()
+			return nil
+		},
+		cap.WithReadiness(),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1, WaitDoneWorker("child2")),
+		[]cap.Opt{cap.WithWaitForReadiness(1 * time.Second)},
+		func(EventManager) {},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerReady("root/child1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/child2"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestWaitForReadinessChildFinished(t *testing.T) {
+	child1 := cap.NewWorker(
+		"child1",
+		func(ctx context.Context) error {
+			// the worker finishes before it is ready
+			return nil
+		},
+		cap.WithReadiness(),
+		cap.WithRestart(cap.Temporary),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1, WaitDoneWorker("child2")),
+		[]cap.Opt{cap.WithWaitForReadiness(1 * time.Second)},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(WorkerCompleted("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+			WorkerCompleted("root/child1"),
+			WorkerTerminated("root/child2"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
+
+func TestWaitForReadinessTimeout(t *testing.T) {
+	readinessTimeout := 50 * time.Millisecond
+	child1, readyChild1 := readyOnSignalWorker("child1")
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1, WaitDoneWorker("child2")),
+		[]cap.Opt{cap.WithWaitForReadiness(readinessTimeout)},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			// the worker is late, it reports it is ready after its siblings started
+			readyChild1()
+			evIt.SkipTill(WorkerReady("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+			WorkerReady("root/child1"),
+			WorkerTerminated("root/child2"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+
+	startDelay := events[1].GetCreated().Sub(events[0].GetCreated())
+	assert.True(
+		t,
+		startDelay >= readinessTimeout,
+		"start delay %v is lower than %v", startDelay, readinessTimeout,
+	)
+}
+
+func TestReadinessOfFailedRuntime(t *testing.T) {
+	var runCount int32
+	child1 := cap.NewWorker(
+		"child1",
+		func(ctx context.Context) error {
+			if atomic.AddInt32(&runCount, 1) == 1 {
+				// the first runtime is ready, and fails right away
+				cap.NotifyReady(ctx)
+				return errors.New("boom")
+			}
+			// the second runtime never gets ready
+			<-ctx.Done()
+			return nil
+		},
+		cap.WithReadiness(),
+		cap.WithRestartBackoff(50*time.Millisecond, 50*time.Millisecond, 1, 0),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(WorkerFailed("root/child1"))
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	// the first runtime may report it is ready before it fails, but never after
+	failedIx := -1
+	for i, ev := range events {
+		if ev.GetTag() == cap.ProcessFailed {
+			failedIx = i
+			break
+		}
+	}
+	if assert.True(t, failedIx >= 0) {
+		for _, ev := range events[failedIx:] {
+			assert.NotEqual(
+				t,
+				cap.ProcessReady,
+				ev.GetTag(),
+				"runtime reported it is ready after it failed",
+			)
+		}
+	}
+}
+
+func TestReadinessAfterAbandonedRunExited(t *testing.T) {
+	evManager := NewEventManager()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	evManager.StartCollector(ctx)
+
+	healthcheckMonitor := cap.NewHealthcheckMonitor(0, 0)
+	collector := evManager.EventCollector(ctx)
+
+	// releases the goroutine of the first run, which does not stop on time
+	releaseCh := make(chan struct{})
+	var runCount int32
+
+	child1 := cap.NewWorker(
+		"child1",
+		func(ctx context.Context) error {
+			if atomic.AddInt32(&runCount, 1) == 1 {
+				<-releaseCh
+				return nil
+			}
+			// the second run never gets ready
+			<-ctx.Done()
+			return nil
+		},
+		cap.WithReadiness(),
+		cap.WithShutdown(cap.Timeout(10*time.Millisecond)),
+	)
+
+	spec := cap.NewSupervisorSpec(
+		"root",
+		cap.WithNodes(child1),
+		cap.WithNotifier(func(ev cap.Event) {
+			healthcheckMonitor.HandleEvent(ev)
+			collector(ev)
+		}),
+	)
+
+	sup, err := spec.Start(ctx)
+	assert.NoError(t, err)
+
+	evIt := evManager.Iterator()
+	evIt.SkipTill(SupervisorStarted("root"))
+
+	var timeoutErr *cap.ShutdownTimeoutError
+	err = sup.TerminateChild(ctx, "root/child1")
+	assert.True(t, errors.As(err, &timeoutErr))
+	evIt.SkipTill(WorkerAbandoned("root/child1"))
+
+	assert.NoError(t, sup.RestartChild(ctx, "root/child1"))
+	evIt.SkipTill(WorkerStarted("root/child1"))
+	assert.False(t, healthcheckMonitor.IsReady())
+
+	// the exit of the abandoned run does not make the new run ready
+	close(releaseCh)
+	evIt.SkipTill(WorkerAbandonedExited("root/child1"))
+	assert.False(t, healthcheckMonitor.IsReady())
+	assert.Equal(
+		t,
+		map[string]bool{"root/child1": true},
+		healthcheckMonitor.GetHealthReport().GetNotReadyProcesses(),
+	)
+
+	assert.NoError(t, sup.Terminate())
+	assert.True(t, healthcheckMonitor.IsReady())
+}
+
+func TestWaitForReadinessNotOnRestart(t *testing.T) {
+	readinessTimeout := 1 * time.Second
+
+	for _, strategy := range []cap.Strategy{cap.OneForAll, cap.RestForOne} {
+		var runCount int32
+		child2 := cap.NewWorker(
+			"child2",
+			func(ctx context.Context) error {
+				// only the first run of the worker gets ready
+				if atomic.AddInt32(&runCount, 1) == 1 {
+					cap.NotifyReady(ctx)
+				}
+				<-ctx.Done()
+				return nil
+			},
+			cap.WithReadiness(),
+		)
+		child1, failChild1 := FailOnSignalWorker(1, "child1")
+
+		events, err := ObserveSupervisor(
+			context.TODO(),
+			"root",
+			cap.WithNodes(child1, child2, WaitDoneWorker("child3")),
+			[]cap.Opt{
+				cap.WithStrategy(strategy),
+				cap.WithWaitForReadiness(readinessTimeout),
+			},
+			func(em EventManager) {
+				evIt := em.Iterator()
+				evIt.SkipTill(SupervisorStarted("root"))
+				failChild1(true /* done */)
+				evIt.SkipTill(WorkerFailed("root/child1"))
+				evIt.SkipTill(WorkerStarted("root/child3"))
+			},
+		)
+
+		assert.NoError(t, err)
+
+		// the restart starts the sibling of child2 without waiting for it
+		startTimes := make(map[string][]time.Time)
+		for _, ev := range events {
+			if ev.GetTag() == cap.ProcessStarted {
+				name := ev.GetProcessRuntimeName()
+				startTimes[name] = append(startTimes[name], ev.GetCreated())
+			}
+		}
+		if !assert.Len(t, startTimes["root/child2"], 2) ||
+			!assert.Len(t, startTimes["root/child3"], 2) {
+			continue
+		}
+		assert.True(
+			t,
+			startTimes["root/child3"][1].Sub(startTimes["root/child2"][1]) < readinessTimeout,
+			"%v restart waited for the readiness of child2", strategy,
+		)
+	}
+}
@@ -0,0 +1,76 @@
+package s
+
+import (
+	"context"
+	"time"
+
+	"github.com/capatazlib/go-capataz/internal/c"
+)
+
+var restForOneRestart strategyRestartFn = func(
+	supCtx context.Context,
+	spec SupervisorSpec, supChildrenSpecs []c.ChildSpec,
+
+	supRuntimeName string,
+	supChildren0 map[string]c.Child,
+	supNotifyChan chan c.ChildNotification,
+
+	sourceCh c.Child,
+) (map[string]c.Child, error) {
+	startSpecs := spec.order.sortStart(supChildrenSpecs)
+
+	sourceIx, ok := getChildIndex(startSpecs, sourceCh)
+	if !ok {
+		panic("failing child is not part of the supervisor specs, check restForOneRestart implementation")
+	}
+
+	// restartNames contains the failing child and all the siblings that got
+	// started after it
+	restartNames := make(map[string]struct{}, len(startSpecs)-sourceIx)
+	for _, chSpec := range startSpecs[sourceIx:] {
+		restartNames[chSpec.GetName()] = struct{}{}
+	}
+
+	// we do not want to stop the restart procedure if a termination fails,
+	// nonetheless, this error is not going unnoticed given the event
+	// notifier gets called on child termination.
+	_ /* nodeErrMap */ = terminateChildNodes(
+		spec, supChildrenSpecs, supChildren0,
+		func(i int, chSpec c.ChildSpec) bool {
+			return skipChildNotIn(restartNames)(i, chSpec) || skipChild(sourceCh)(i, chSpec)
+		},
+		time.Time{},
+	)
+
+	// siblings started before the failing child are kept as they are
+	supChildren := make(map[string]c.Child, len(supChildren0))
+	for chName, ch := range supChildren0 {
+		if _, ok := restartNames[chName]; !ok {
+			supChildren[chName] = ch
+		}
+	}
+
+	restartedChildren, restartErr := startChildNodes(
+		supCtx,
+		spec,
+		supChildrenSpecs,
+		supRuntimeName,
+		supNotifyChan,
+		skipChildNotIn(restartNames),
+		// restarts do not block the monitor loop on the readiness of children
+		0,
+	)
+
+	if restartErr != nil {
+		// Very important! even though we return an error value here, we want to
+		// return the siblings that were not restarted, otherwise they won't get
+		// terminated appropietly.
+		return supChildren, restartErr
+	}
+
+	for chName, ch := range restartedChildren {
+		supChildren[chName] = ch
+	}
+
+	return supChildren, nil
+}
@@ -0,0 +1,90 @@
+package s
+
+import (
+	"time"
+
+	"github.com/capatazlib/go-capataz/internal/c"
+)
+
+// pendingRestart is the record of a child restart that is waiting for its delay
+// to be done
+type pendingRestart struct {
+	timer     *time.Timer
+	createdAt time.Time
+}
+
+// restartScheduler offers an API to delay the restart of a child node without
+// blocking the supervisor monitor loop. When the delay of a child is done, the
+// child is sent back to the monitor loop through the restartCh channel.
+type restartScheduler struct {
+	restartCh chan c.Child
+	doneCh    chan struct{}
+	pending   map[string]pendingRestart
+}
+
+// newRestartScheduler creates a new restartScheduler
+func newRestartScheduler() *restartScheduler {
+	return &restartScheduler{
+		restartCh: make(chan c.Child),
+		doneCh:    make(chan struct{}),
+		pending:   make(map[string]pendingRestart),
+	}
+}
+
+// schedule sends the given child to the restartCh after the given delay. This
+// function must be called from the supervisor monitor loop.
+func (rs *restartScheduler) schedule(ch c.Child, delay time.Duration) {
+	if prev, ok := rs.pending[ch.GetName()]; ok {
+		prev.timer.Stop()
+	}
+	rs.pending[ch.GetName()] = pendingRestart{
+		createdAt: ch.GetCreatedAt(),
+		timer: time.AfterFunc(delay, func() {
+			select {
+			case rs.restartCh <- ch:
+			case <-rs.doneCh:
+			}
+		}),
+	}
+}
+
+// isPending returns true if the given child runtime is waiting for a delayed
+// restart. A child may get restarted by a sibling failure (or removed) while
+// its delayed restart is pending; in that case the child is not pending
+// anymore.
+func (rs *restartScheduler) isPending(
+	supChildren map[string]c.Child,
+	chName string,
+) bool {
+	pending, ok := rs.pending[chName]
+	if !ok {
+		return false
+	}
+	current, ok := supChildren[chName]
+	return ok && current.GetCreatedAt().Equal(pending.createdAt)
+}
+
+// isCurrent returns true if the given child (received from the restartCh) is
+// still the latest runtime of the child node in the given supervisor children.
+// When this function returns false, the scheduled restart must be discarded.
+func (rs *restartScheduler) isCurrent(
+	supChildren map[string]c.Child,
+	ch c.Child,
+) bool {
+	pending, ok := rs.pending[ch.GetName()]
+	if !ok || !pending.createdAt.Equal(ch.GetCreatedAt()) {
+		return false
+	}
+	delete(rs.pending, ch.GetName())
+	current, ok := supChildren[ch.GetName()]
+	return ok && current.GetCreatedAt().Equal(ch.GetCreatedAt())
+}
+
+// stop cancels all the pending restarts, it must be called when the
+// supervisor monitor loop is done.
+func (rs *restartScheduler) stop() {
+	for _, pending := range rs.pending {
+		pending.timer.Stop()
+	}
+	close(rs.doneCh)
+}
@@ -0,0 +1,194 @@
+package s_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestRestartBackoffDoesNotBlockSiblings(t *testing.T) {
+	parentName := "root"
+	backoff := 200 * time.Millisecond
+
+	child1, failWorker1 := FailOnSignalWorker(
+		1,
+		"child1",
+		cap.WithRestart(cap.Permanent),
+		cap.WithRestartBackoff(backoff, 1*time.Second, 2, 0),
+	)
+	child2, failWorker2 := FailOnSignalWorker(
+		1,
+		"child2",
+		cap.WithRestart(cap.Permanent),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		parentName,
+		cap.WithNodes(child1, child2),
+		[]cap.Opt{
+			cap.WithRestartTolerance(10, 10*time.Second),
+		},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			// 1) child1 fails, and its restart gets delayed
+			failWorker1(true /* done */)
+			evIt.SkipTill(WorkerFailed("root/child1"))
+
+			// 2) child2 fails and gets restarted while child1 is waiting
+			failWorker2(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child2"))
+
+			// 3) child1 gets restarted after the backoff
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+			WorkerFailed("root/child1"),
+			WorkerFailed("root/child2"),
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/child1"),
+			WorkerTerminated("root/child2"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+
+	restartDelay := events[6].GetCreated().Sub(events[3].GetCreated())
+	assert.True(t, restartDelay >= backoff, "restart delay %v is lower than %v", restartDelay, backoff)
+}
+
+func TestRestartBackoffGrowsOnConsecutiveFailures(t *testing.T) {
+	parentName := "root"
+	backoff := 20 * time.Millisecond
+
+	child1, failWorker1 := FailOnSignalWorker(
+		3,
+		"child1",
+		cap.WithRestart(cap.Permanent),
+		cap.WithRestartBackoff(backoff, 1*time.Second, 2, 0),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		parentName,
+		cap.WithNodes(child1),
+		[]cap.Opt{
+			cap.WithRestartTolerance(10, 10*time.Second),
+		},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			for i := 0; i < 3; i++ {
+				failWorker1(false /* done */)
+				evIt.SkipTill(WorkerStarted("root/child1"))
+			}
+		},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			WorkerFailed("root/child1"),
+			WorkerStarted("root/child1"),
+			WorkerFailed("root/child1"),
+			WorkerStarted("root/child1"),
+			WorkerFailed("root/child1"),
+			WorkerStarted("root/child1"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+
+	for i, failIx := range []int{2, 4, 6} {
+		expected := backoff * time.Duration(1<<i)
+		restartDelay := events[failIx+1].GetCreated().Sub(events[failIx].GetCreated())
+		assert.True(
+			t,
+			restartDelay >= expected,
+			"restart %d delay %v is lower than %v", i, restartDelay, expected,
+		)
+	}
+}
+
+func TestRestartBackoffStaysAtMax(t *testing.T) {
+	parentName := "root"
+	backoff := 20 * time.Millisecond
+	maxBackoff := 80 * time.Millisecond
+
+	child1, failWorker1 := FailOnSignalWorker(
+		5,
+		"child1",
+		cap.WithRestart(cap.Permanent),
+		cap.WithRestartBackoff(backoff, maxBackoff, 2, 0),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		parentName,
+		cap.WithNodes(child1),
+		[]cap.Opt{
+			cap.WithRestartTolerance(10, 10*time.Second),
+		},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			for i := 0; i < 5; i++ {
+				failWorker1(false /* done */)
+				evIt.SkipTill(WorkerStarted("root/child1"))
+			}
+		},
+	)
+
+	assert.NoError(t, err)
+
+	expectedEvents := []EventP{
+		WorkerStarted("root/child1"),
+		SupervisorStarted("root"),
+	}
+	for i := 0; i < 5; i++ {
+		expectedEvents = append(
+			expectedEvents,
+			WorkerFailed("root/child1"),
+			WorkerStarted("root/child1"),
+		)
+	}
+	expectedEvents = append(
+		expectedEvents,
+		WorkerTerminated("root/child1"),
+		SupervisorTerminated("root"),
+	)
+	AssertExactMatch(t, events, expectedEvents)
+
+	// the delay of a restart does not count as uptime of the child, so the
+	// backoff does not get reset once it reaches its max
+	expectedDelays := []time.Duration{backoff, 2 * backoff, maxBackoff, maxBackoff, maxBackoff}
+	for i, expected := range expectedDelays {
+		failIx := 2 + i*2
+		restartDelay := events[failIx+1].GetCreated().Sub(events[failIx].GetCreated())
+		assert.True(
+			t,
+			restartDelay >= expected,
+			"restart %d delay %v is lower than %v", i, restartDelay, expected,
+		)
+	}
+}
@@ -0,0 +1,291 @@
+package s_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+// restartEventsCollector returns a notifier that keeps track of the events
+// that report the restart bookkeeping of the supervision system, these events
+// are not collected by the EventManager
+func restartEventsCollector() (cap.EventNotifier, func() []cap.Event) {
+	var mux sync.Mutex
+	var events []cap.Event
+
+	notifier := func(ev cap.Event) {
+		switch ev.GetTag() {
+		case cap.SupervisorRestarting,
+			cap.RestartToleranceIncremented,
+			cap.RestartToleranceWindowReset,
+			cap.ChildRestartScheduled:
+			mux.Lock()
+			defer mux.Unlock()
+			events = append(events, ev)
+		}
+	}
+
+	snapshot := func() []cap.Event {
+		mux.Lock()
+		defer mux.Unlock()
+		return append(events[:0:0], events...)
+	}
+
+	return notifier, snapshot
+}
+
+func TestRestartEventsOneForOne(t *testing.T) {
+	child1, failWorker1 := FailOnSignalWorker(2, "child1", cap.WithRestart(cap.Permanent))
+	notifier, restartEvents := restartEventsCollector()
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1, WaitDoneWorker("child2")),
+		[]cap.Opt{cap.WithRestartTolerance(3, 10*time.Second)},
+		[]cap.EventNotifier{notifier},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	events := restartEvents()
+	AssertExactMatch(t, events,
+		[]EventP{
+			SupervisorRestartToleranceIncremented("root", 1),
+			SupervisorRestarting("root", "root/child1"),
+			SupervisorRestartToleranceIncremented("root", 2),
+			SupervisorRestarting("root", "root/child1"),
+		},
+	)
+
+	for _, ev := range events {
+		assert.Equal(t, "root/child1", ev.GetSourceRuntimeName())
+		assert.Equal(t, uint32(3), ev.GetMaxRestartCount())
+		assert.Equal(t, 10*time.Second, ev.GetRestartWindow())
+	}
+	assert.Equal(t, cap.OneForOne, events[1].GetRestartStrategy())
+	assert.Equal(t, uint32(2), events[3].GetRestartCount())
+}
+
+func TestRestartEventsOneForAll(t *testing.T) {
+	child2, failWorker2 := FailOnSignalWorker(1, "child2", cap.WithRestart(cap.Permanent))
+	notifier, restartEvents := restartEventsCollector()
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(WaitDoneWorker("child1"), child2),
+		[]cap.Opt{
+			cap.WithStrategy(cap.OneForAll),
+			cap.WithRestartTolerance(3, 10*time.Second),
+		},
+		[]cap.EventNotifier{notifier},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			failWorker2(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child2"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	events := restartEvents()
+	AssertExactMatch(t, events,
+		[]EventP{
+			SupervisorRestartToleranceIncremented("root", 1),
+			SupervisorRestarting("root", "root/child2"),
+		},
+	)
+	assert.Equal(t, cap.OneForAll, events[1].GetRestartStrategy())
+}
+
+func TestRestartEventsWindowReset(t *testing.T) {
+	restartWindow := 20 * time.Millisecond
+	child1, failWorker1 := FailOnSignalWorker(2, "child1", cap.WithRestart(cap.Permanent))
+	notifier, restartEvents := restartEventsCollector()
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{cap.WithRestartTolerance(1, restartWindow)},
+		[]cap.EventNotifier{notifier},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+
+			// wait for the restart window to expire
+			time.Sleep(2 * restartWindow)
+
+			failWorker1(false /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+
+	// the supervisor does not fail, as the second error happened on a new
+	// restart window
+	assert.NoError(t, err)
+
+	events := restartEvents()
+	AssertExactMatch(t, events,
+		[]EventP{
+			SupervisorRestartToleranceIncremented("root", 1),
+			SupervisorRestarting("root", "root/child1"),
+			SupervisorRestartToleranceWindowReset("root"),
+			SupervisorRestarting("root", "root/child1"),
+		},
+	)
+	assert.Equal(t, uint32(1), events[2].GetRestartCount())
+	assert.Equal(t, restartWindow, events[2].GetRestartWindow())
+}
+
+func TestRestartEventsChildTolerance(t *testing.T) {
+	child1, failWorker1 := FailOnSignalWorker(
+		1,
+		"child1",
+		cap.WithRestart(cap.Permanent),
+		cap.WithTolerance(2, 10*time.Second),
+	)
+	notifier, restartEvents := restartEventsCollector()
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		[]cap.EventNotifier{notifier},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			failWorker1(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	events := restartEvents()
+	AssertExactMatch(t, events,
+		[]EventP{
+			// the child accounts the error on its own tolerance first
+			WorkerRestartToleranceIncremented("root/child1", 1),
+			SupervisorRestartToleranceIncremented("root", 1),
+			SupervisorRestarting("root", "root/child1"),
+		},
+	)
+	assert.Equal(t, uint32(2), events[0].GetMaxRestartCount())
+	assert.Equal(t, 10*time.Second, events[0].GetRestartWindow())
+}
+
+func TestRestartEventsScheduledRestart(t *testing.T) {
+	backoff := 20 * time.Millisecond
+	child1, failWorker1 := FailOnSignalWorker(
+		1,
+		"child1",
+		cap.WithRestart(cap.Permanent),
+		cap.WithRestartBackoff(backoff, time.Second, 2, 0),
+	)
+	notifier, restartEvents := restartEventsCollector()
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		[]cap.EventNotifier{notifier},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			failWorker1(true /* done */)
+			evIt.SkipTill(WorkerStarted("root/child1"))
+		},
+	)
+
+	assert.NoError(t, err)
+
+	events := restartEvents()
+	AssertExactMatch(t, events,
+		[]EventP{
+			SupervisorRestartToleranceIncremented("root", 1),
+			WorkerRestartScheduled("root/child1"),
+			// the restart tolerance is not accounted again once the delay is
+			// done
+			SupervisorRestarting("root", "root/child1"),
+		},
+	)
+	assert.Equal(t, "root/child1", events[1].GetSourceRuntimeName())
+	assert.Equal(t, backoff, events[1].GetRestartDelay())
+	// the child does not have a restart tolerance of its own
+	assert.Equal(t, uint32(0), events[1].GetRestartCount())
+	assert.Equal(t, uint32(0), events[1].GetMaxRestartCount())
+}
+
+func TestRestartEventsScheduledRestartChildTolerance(t *testing.T) {
+	child1, failWorker1 := FailOnSignalWorker(
+		2,
+		"child1",
+		cap.WithRestart(cap.Permanent),
+		cap.WithRestartBackoff(20*time.Millisecond, time.Second, 2, 0),
+		cap.WithTolerance(3, time.Minute),
+	)
+	notifier, restartEvents := restartEventsCollector()
+
+	_, err := ObserveSupervisorWithNotifiers(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{cap.WithRestartTolerance(10, time.Minute)},
+		[]cap.EventNotifier{notifier},
+		func(em EventManager) {
+			evIt := em.Iterator()
+			evIt.SkipTill(SupervisorStarted("root"))
+
+			for i := 0; i < 2; i++ {
+				failWorker1(false /* done */)
+				evIt.SkipTill(WorkerStarted("root/child1"))
+			}
+		},
+	)
+
+	assert.NoError(t, err)
+
+	var scheduled []cap.Event
+	for _, ev := range restartEvents() {
+		if ev.GetTag() == cap.ChildRestartScheduled {
+			scheduled = append(scheduled, ev)
+		}
+	}
+
+	// the restart bookkeeping of the event comes from the tolerance of the
+	// child, not from the tolerance of the supervisor
+	if assert.Len(t, scheduled, 2) {
+		for i, ev := range scheduled {
+			assert.Equal(t, uint32(i+1), ev.GetRestartCount())
+			assert.Equal(t, uint32(3), ev.GetMaxRestartCount())
+			assert.Equal(t, time.Minute, ev.GetRestartWindow())
+		}
+	}
+}
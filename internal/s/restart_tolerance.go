@@ -2,6 +2,8 @@ package s
 
 import (
 	"time"
+
+	"github.com/capatazlib/go-capataz/internal/c"
 )
 
 // restartToleranceResult indicates the result of a error tolerance check
@@ -54,3 +56,50 @@ func (rt restartTolerance) check(restartCount uint32, createdAt time.Time) resta
 	}
 	return resetRestartCount
 }
+
+// checkChildToleranceExceeded adds a new failure on the restart tolerance of
+// the given child (if it has one). If the number of errors is enough to surpass
+// the child tolerance, it returns a RestartToleranceReached error, otherwise it
+// returns the child with an updated tolerance state.
+func checkChildToleranceExceeded(
+	eventNotifier EventNotifier,
+	ch c.Child,
+	err error,
+) (c.Child, *RestartToleranceReached) {
+	chTolerance := ch.GetSpec().GetTolerance()
+	if chTolerance == nil {
+		return ch, nil
+	}
+
+	errCount, beginTime, sourceErr := ch.GetToleranceState()
+	mgr := restartToleranceManager{
+		sourceErr: sourceErr,
+		restartTolerance: restartTolerance{
+			MaxRestartCount: chTolerance.GetMaxErrCount(),
+			RestartWindow:   chTolerance.GetErrWindow(),
+		},
+		restartCount:     errCount,
+		restartBeginTime: beginTime,
+	}
+
+	check := mgr.checkToleranceExceeded(err)
+	if check == restartToleranceSurpassed {
+		return ch, NewRestartToleranceReached(
+			mgr.restartTolerance,
+			ch,
+			mgr.sourceErr,
+			err,
+		)
+	}
+
+	chName := ch.GetRuntimeName()
+	eventNotifier.restartToleranceUpdated(
+		ch.GetTag(), chName, chName, check, &mgr,
+	)
+
+	return ch.WithToleranceState(
+		mgr.restartCount,
+		mgr.restartBeginTime,
+		mgr.sourceErr,
+	), nil
+}
@@ -92,8 +92,17 @@ func (spec SupervisorSpec) rootStart(
 
 	supRuntimeName := buildRuntimeName(spec, parentName)
 
+	// the nodes of the tree, including the supervisor itself, run with the
+	// context returned by the nodeContextFn
+	if spec.nodeContextFn != nil {
+		supCtx = spec.nodeContextFn(supCtx, supRuntimeName, c.Supervisor)
+		supCtx = c.WithNodeContextFn(supCtx, spec.nodeContextFn)
+	}
+
 	eventNotifier := spec.getEventNotifier()
 	supCtx = withEventNotifier(supCtx, eventNotifier)
+	// supEventNotifier reports the events of the root supervisor itself
+	supEventNotifier := eventNotifier.withLabels(spec.getLabels())
 
 	// Build childrenSpec and resource cleanup
 	childrenSpecs, supRscCleanup, rscAllocError := spec.buildChildrenSpecs(supRuntimeName)
@@ -102,7 +111,7 @@ func (spec SupervisorSpec) rootStart(
 	// allocation logic
 	if rscAllocError != nil {
 		cancelFn()
-		eventNotifier.supervisorStartFailed(supRuntimeName, rscAllocError)
+		supEventNotifier.supervisorStartFailed(supRuntimeName, rscAllocError)
 		return Supervisor{}, rscAllocError
 	}
 
@@ -116,8 +125,9 @@ func (spec SupervisorSpec) rootStart(
 		terminateCh:      terminateCh,
 		terminateManager: tm,
 
-		spec:     spec,
-		children: make(map[string]c.Child, len(childrenSpecs)),
+		spec:      spec,
+		children:  make(map[string]c.Child, len(childrenSpecs)),
+		createdAt: time.Now(),
 
 		cancel: cancelFn,
 		wait: func(stopingTime time.Time, startErr error) error {
@@ -125,7 +135,7 @@ func (spec SupervisorSpec) rootStart(
 			// We check if there was an start error reported, if this is the case, we
 			// notify that the supervisor start failed
 			if startErr != nil {
-				eventNotifier.supervisorStartFailed(supRuntimeName, startErr)
+				supEventNotifier.supervisorStartFailed(supRuntimeName, startErr)
 				return startErr
 			}
 
@@ -135,7 +145,7 @@ func (spec SupervisorSpec) rootStart(
 			// otherwise it will return nil
 			_, supErr := getCrashError(
 				true, /* block */
-				eventNotifier,
+				supEventNotifier,
 				supRuntimeName,
 				terminateCh,
 				tm,
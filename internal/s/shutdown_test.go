@@ -0,0 +1,191 @@
+package s_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+// hangingWorker creates a worker that does not respect its context, it stops
+// once the given channel is closed
+func hangingWorker(name string, releaseCh <-chan struct{}, opts ...cap.WorkerOpt) cap.Node {
+	return cap.NewWorker(
+		name,
+		func(ctx context.Context) error {
+			<-releaseCh
+			return nil
+		},
+		opts...,
+	)
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	releaseCh := make(chan struct{})
+	defer close(releaseCh)
+
+	startTime := time.Now()
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(
+			// the supervisor timeout is shorter than the child's shutdown
+			hangingWorker("child1", releaseCh, cap.WithShutdown(cap.Indefinitely)),
+			WaitDoneWorker("child2"),
+		),
+		[]cap.Opt{cap.WithShutdownTimeout(50 * time.Millisecond)},
+		func(em EventManager) {},
+	)
+	// the supervisor does not wait for child1 to finish
+	assert.True(t, time.Since(startTime) < time.Second)
+
+	assert.Error(t, err)
+	kvs := err.(cap.ErrKVs).KVs()
+	assert.Equal(t, "root", kvs["supervisor.name"])
+	assert.Equal(t, "child1", kvs["supervisor.termination.node.0.name"])
+	assert.Equal(
+		t,
+		"child shutdown timeout",
+		fmt.Sprint(kvs["supervisor.termination.node.0.error"]),
+	)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/child2"),
+			WorkerFailedWith("root/child1", "child shutdown timeout"),
+			WorkerAbandoned("root/child1"),
+			SupervisorFailed("root"),
+		},
+	)
+}
+
+func TestShutdownTimeoutBoundsChildrenTimeouts(t *testing.T) {
+	releaseCh := make(chan struct{})
+	defer close(releaseCh)
+
+	// every child takes most of the supervisor timeout before giving up
+	childShutdown := cap.WithShutdown(cap.Timeout(40 * time.Millisecond))
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(
+			hangingWorker("child1", releaseCh, childShutdown),
+			hangingWorker("child2", releaseCh, childShutdown),
+			hangingWorker("child3", releaseCh, childShutdown),
+		),
+		[]cap.Opt{cap.WithShutdownTimeout(50 * time.Millisecond)},
+		func(em EventManager) {},
+	)
+
+	assert.Error(t, err)
+	kvs := err.(cap.ErrKVs).KVs()
+	// all children are reported, even the ones that were not waited for once
+	// the supervisor timeout was reached
+	assert.Equal(t, "child1", kvs["supervisor.termination.node.0.name"])
+	assert.Equal(t, "child2", kvs["supervisor.termination.node.1.name"])
+	assert.Equal(t, "child3", kvs["supervisor.termination.node.2.name"])
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStarted("root/child2"),
+			WorkerStarted("root/child3"),
+			SupervisorStarted("root"),
+			WorkerFailed("root/child3"),
+			WorkerAbandoned("root/child3"),
+			WorkerFailed("root/child2"),
+			WorkerAbandoned("root/child2"),
+			WorkerFailed("root/child1"),
+			WorkerAbandoned("root/child1"),
+			SupervisorFailed("root"),
+		},
+	)
+}
+
+func TestSubtreeShutdownTimeout(t *testing.T) {
+	testCases := []struct {
+		name        string
+		subtreeOpts []cap.Opt
+		nodeOpts    []cap.WorkerOpt
+	}{
+		{
+			name:        "supervisor option",
+			subtreeOpts: []cap.Opt{cap.WithShutdownTimeout(50 * time.Millisecond)},
+		},
+		{
+			name: "subtree option",
+			nodeOpts: []cap.WorkerOpt{
+				cap.WithShutdown(cap.Timeout(50 * time.Millisecond)),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			releaseCh := make(chan struct{})
+			defer close(releaseCh)
+
+			// the sub-tree reports the failure of its child once its own timeout is
+			// reached, even if the parent gave up on the sub-tree before
+			childFailedCh := make(chan struct{})
+			notifier := func(ev cap.Event) {
+				if WorkerFailed("root/branch1/child1").Call(ev) {
+					close(childFailedCh)
+				}
+			}
+
+			branch1 := cap.NewSupervisorSpec(
+				"branch1",
+				cap.WithNodes(
+					hangingWorker("child1", releaseCh, cap.WithShutdown(cap.Indefinitely)),
+				),
+				tc.subtreeOpts...,
+			)
+
+			rootSpec := cap.NewSupervisorSpec(
+				"root",
+				cap.WithNodes(cap.Subtree(branch1, tc.nodeOpts...)),
+				cap.WithNotifier(notifier),
+			)
+
+			sup, err := rootSpec.Start(context.TODO())
+			assert.NoError(t, err)
+
+			terminateErrCh := make(chan error, 1)
+			go func() {
+				terminateErrCh <- sup.Terminate()
+			}()
+
+			select {
+			case err := <-terminateErrCh:
+				// depending on which timeout is reached first, the error is
+				// reported by the root supervisor or by the sub-tree
+				assert.Error(t, err)
+			case <-time.After(time.Second):
+				assert.Fail(t, "root supervisor did not honour the subtree shutdown")
+				return
+			}
+
+			select {
+			case <-childFailedCh:
+			case <-time.After(time.Second):
+				assert.Fail(t, "subtree supervisor did not honour its shutdown")
+			}
+		})
+	}
+}
+
+func TestWithShutdownTimeoutInvalid(t *testing.T) {
+	assert.Panics(t, func() {
+		cap.WithShutdownTimeout(0)
+	})
+}
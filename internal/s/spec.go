@@ -3,6 +3,7 @@ package s
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 	"time"
 
 	"github.com/capatazlib/go-capataz/internal/c"
@@ -66,9 +67,26 @@ const (
 	// OneForAll is an Strategy that tells the Supervisor to restart all the
 	// siblings of a failed child process
 	OneForAll
-	// RestForOne
+	// RestForOne is an Strategy that tells the Supervisor to restart the failed
+	// child process and all the siblings that were started after it (following
+	// the supervisor's start Order)
+	RestForOne
 )
 
+// String returns a string representation of the current Strategy
+func (s Strategy) String() string {
+	switch s {
+	case OneForOne:
+		return "OneForOne"
+	case OneForAll:
+		return "OneForAll"
+	case RestForOne:
+		return "RestForOne"
+	default:
+		return "<Unknown>"
+	}
+}
+
 // getEventNotifier returns the configured EventNotifier or emptyEventNotifier
 // (if none is given via WithEventNotifier)
 func (spec SupervisorSpec) getEventNotifier() EventNotifier {
@@ -106,8 +124,37 @@ type SupervisorSpec struct {
 	buildNodes       BuildNodesFn
 	order            Order
 	strategy         Strategy
-	shutdownTimeout  time.Duration
+	shutdown         c.Shutdown
 	eventNotifier    EventNotifier
+
+	// panicPolicy is the panic policy of the children that do not specify one
+	// of their own, the zero value restarts children that panic
+	panicPolicy c.PanicPolicy
+
+	// labels are attached to the events of the supervisor, and inherited by its
+	// children
+	labels map[string]string
+
+	// nodeContextFn is called every time a node of the supervision tree starts,
+	// it is nil when the context of the nodes is not modified
+	nodeContextFn c.NodeContextFn
+
+	// dynChildren is not nil when the children of the supervisor are spawned at
+	// runtime (e.g. DynSupervisor); children that are not going to be restarted
+	// are removed from the supervisor children
+	dynChildren *dynChildrenState
+
+	// childrenLimit restricts the number of children the supervisor may spawn
+	// at runtime, it is nil when there is no limit
+	childrenLimit *childrenLimit
+
+	// readinessTimeout is the time the supervisor waits for a child to be ready
+	// before starting the next one, it is zero when the supervisor does not wait
+	readinessTimeout time.Duration
+
+	// inbox delivers the events of goroutines spawned by the supervisor to the
+	// monitor loop, it is set by the monitor loop
+	inbox *monitorInbox
 }
 
 // reliableBuildNodes capture panics returned from the buildNodes client
@@ -122,6 +169,7 @@ func reliableBuildNodes(
 			err = &SupervisorBuildError{
 				supRuntimeName: supRuntimeName,
 				buildNodesErr:  fmt.Errorf("%v", panicVal),
+				panicErr:       c.NewPanicError(panicVal, debug.Stack()),
 			}
 		}
 	}()
@@ -140,12 +188,29 @@ func (spec SupervisorSpec) buildChildrenSpecs(
 	}
 
 	children := make([]c.ChildSpec, 0, len(nodes))
-	for _, buildChildSpec := range nodes {
-		children = append(children, buildChildSpec(spec))
+	for _, node := range nodes {
+		children = append(children, spec.buildNode(node))
 	}
 	return children, cleanup, nil
 }
 
+// buildNode constructs the childSpec of the given node, children that do not
+// specify a panic policy of their own get the panic policy of the supervisor,
+// and all children inherit the labels of the supervisor
+func (spec SupervisorSpec) buildNode(node Node) c.ChildSpec {
+	chSpec := node(spec)
+	if chSpec.InheritsPanicPolicy() {
+		chSpec.PanicPolicy = spec.panicPolicy
+	}
+	chSpec.Labels = c.MergeLabels(spec.labels, chSpec.Labels)
+	return chSpec
+}
+
+// getLabels returns the labels attached to the events of the supervisor
+func (spec SupervisorSpec) getLabels() map[string]string {
+	return spec.labels
+}
+
 // NewSupervisorSpec creates a SupervisorSpec. It requires the name of the
 // supervisor (for tracing purposes) and some children nodes to supervise.
 //
@@ -253,7 +318,7 @@ func NewSupervisorSpec(name string, buildNodes BuildNodesFn, opts ...Opt) Superv
 		// http://erlang.org/doc/design_principles/sup_princ.html#maximum-restart-intensity
 		restartTolerance: restartTolerance{MaxRestartCount: 1, RestartWindow: 5 * time.Second},
 		buildNodes:       buildNodes,
-		shutdownTimeout:  defaultSupShutdownTimeout,
+		shutdown:         c.Indefinitely,
 		eventNotifier:    emptyEventNotifier,
 	}
 
@@ -0,0 +1,83 @@
+package s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/capatazlib/go-capataz/cap"
+	. "github.com/capatazlib/go-capataz/internal/stest"
+)
+
+func TestStartTimeout(t *testing.T) {
+	// the worker never notifies it started, it only respects its context
+	child2 := cap.NewWorkerWithNotifyStart(
+		"child2",
+		func(ctx context.Context, _ cap.NotifyStartFn) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		cap.WithStartTimeout(20*time.Millisecond),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(WaitDoneWorker("child1"), child2, WaitDoneWorker("child3")),
+		[]cap.Opt{},
+		func(EventManager) {},
+	)
+
+	assert.Error(t, err)
+
+	var startErr *cap.SupervisorStartError
+	assert.True(t, errors.As(err, &startErr))
+
+	var timeoutErr *cap.StartTimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, "root/child2", timeoutErr.GetNodeName())
+	assert.Equal(t, "start timeout after 20ms", timeoutErr.Error())
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			WorkerStartFailed("root/child2"),
+			WorkerTerminated("root/child1"),
+			SupervisorStartFailed("root"),
+		},
+	)
+}
+
+func TestStartTimeoutNotReached(t *testing.T) {
+	child1 := cap.NewWorkerWithNotifyStart(
+		"child1",
+		func(ctx context.Context, notifyStart cap.NotifyStartFn) error {
+			notifyStart(nil)
+			<-ctx.Done()
+			return nil
+		},
+		cap.WithStartTimeout(time.Second),
+	)
+
+	events, err := ObserveSupervisor(
+		context.TODO(),
+		"root",
+		cap.WithNodes(child1),
+		[]cap.Opt{},
+		func(EventManager) {},
+	)
+
+	assert.NoError(t, err)
+
+	AssertExactMatch(t, events,
+		[]EventP{
+			WorkerStarted("root/child1"),
+			SupervisorStarted("root"),
+			WorkerTerminated("root/child1"),
+			SupervisorTerminated("root"),
+		},
+	)
+}
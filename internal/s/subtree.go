@@ -77,6 +77,15 @@ func subtreeMain(
 		}
 		ctx, cancelFn := context.WithCancel(parentCtx)
 		defer cancelFn()
+		// on a Graceful shutdown, the sub-tree starts its termination as soon as
+		// the parent requests it to stop
+		go func() {
+			select {
+			case <-c.ShutdownRequested(parentCtx):
+				cancelFn()
+			case <-ctx.Done():
+			}
+		}()
 		return supSpec.run(ctx, supRuntimeName, notifyChildStart, ctrlChan)
 	}
 }
@@ -90,22 +99,46 @@ func (spec SupervisorSpec) subtree(
 	copts0 ...c.Opt,
 ) c.ChildSpec {
 	subtreeSpec.eventNotifier = spec.eventNotifier
+	// sub-trees without a panic policy of their own inherit the one of their
+	// parent
+	if subtreeSpec.panicPolicy == 0 {
+		subtreeSpec.panicPolicy = spec.panicPolicy
+	}
+
+	// NOTE: The parent waits for the sub-tree supervisor as much as the sub-tree
+	// waits for its own children (Indefinitely by default, as specified in the
+	// documentation from OTP
+	// http://erlang.org/doc/design_principles/sup_princ.html#child-specification).
+	// A WithShutdown option given to Subtree overrides the sub-tree's shutdown.
+	optSpec := c.ChildSpec{Shutdown: subtreeSpec.shutdown}
+	for _, optFn := range copts0 {
+		optFn(&optSpec)
+	}
+	subtreeSpec.shutdown = optSpec.Shutdown
+
+	// the labels of the sub-tree are the labels of its parent, merged with the
+	// labels of the sub-tree spec and the labels given to Subtree
+	subtreeSpec.labels = c.MergeLabels(
+		c.MergeLabels(spec.labels, subtreeSpec.labels),
+		optSpec.Labels,
+	)
 
-	// NOTE: Child goroutines that are running a sub-tree supervisor must always
-	// have a timeout of Infinity, as specified in the documentation from OTP
-	// http://erlang.org/doc/design_principles/sup_princ.html#child-specification
 	copts := append(
 		copts0,
-		c.WithShutdown(c.Indefinitely),
+		c.WithShutdown(subtreeSpec.shutdown),
+		c.WithLabels(subtreeSpec.labels),
 		c.WithTag(c.Supervisor),
-		c.WithTolerance(1, 5*time.Second),
 	)
 
-	return c.NewWithNotifyStart(
+	chSpec := c.NewWithNotifyStart(
 		subtreeSpec.GetName(),
 		subtreeMain(subtreeSpec, ctrlChan),
 		copts...,
 	)
+	// the ctrlChan allows clients to reach the sub-tree supervisor (e.g. to
+	// inspect its children)
+	chSpec.Ctrl = ctrlChan
+	return chSpec
 }
 
 // Subtree transforms SupervisorSpec into a Node. This function allows you to
@@ -66,9 +66,10 @@ type restartToleranceManager struct {
 }
 
 // checkToleranceExceeded adds a new failure on the error tolerance calculation, if the
-// number of errors is enough to surpass tolerance, it will return false,
-// otherwise it will modify it's restart count and return true.
-func (mgr *restartToleranceManager) checkToleranceExceeded(err error) bool {
+// number of errors is enough to surpass tolerance, it will return
+// restartToleranceSurpassed, otherwise it will modify it's restart count and
+// return the change that was done on it.
+func (mgr *restartToleranceManager) checkToleranceExceeded(err error) restartToleranceResult {
 	if mgr.restartBeginTime == (time.Time{}) {
 		mgr.sourceErr = err
 		mgr.restartBeginTime = time.Now()
@@ -79,16 +80,16 @@ func (mgr *restartToleranceManager) checkToleranceExceeded(err error) bool {
 
 	switch check {
 	case restartToleranceSurpassed:
-		return false
+		return check
 	case incRestartCount:
 		mgr.restartCount++
-		return true
+		return check
 	case resetRestartCount:
 		// not zero given we need to account for the error that just happened
 		mgr.sourceErr = err
 		mgr.restartCount = 1
 		mgr.restartBeginTime = time.Now()
-		return true
+		return check
 	default:
 		panic("Invalid implementation of restartTolerance values")
 	}
@@ -108,17 +109,16 @@ type Supervisor struct {
 	terminateManager        *terminationManager
 	restartToleranceManager *restartToleranceManager
 
-	spec     SupervisorSpec
-	children map[string]c.Child
-	cancel   func()
-	wait     func(time.Time, startNodeError) error
+	spec      SupervisorSpec
+	children  map[string]c.Child
+	createdAt time.Time
+	cancel    func()
+	wait      func(time.Time, startNodeError) error
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // Public API
 
-const defaultSupShutdownTimeout = 5 * time.Second
-
 // Terminate is a synchronous procedure that halts the execution of the whole
 // supervision tree.
 func (sup Supervisor) Terminate() error {
@@ -212,7 +212,7 @@ func getCrashError(
 func (sup Supervisor) GetCrashError(block bool) (bool, error) {
 	return getCrashError(
 		false, /* block */
-		sup.spec.eventNotifier,
+		sup.spec.getEventNotifier().withLabels(sup.spec.getLabels()),
 		sup.runtimeName,
 		sup.terminateCh,
 		sup.terminateManager,
@@ -100,6 +100,10 @@ func TestSupervisorWithPanicBuildNodesFnOnSingleTree(t *testing.T) {
 		explanation,
 	)
 
+	var panicErr *cap.PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "single tree panic", panicErr.GetValue())
+
 	AssertExactMatch(t, events,
 		[]EventP{
 			SupervisorStartFailed("root"),
@@ -143,6 +147,10 @@ func TestSupervisorWithPanicBuildNodesFnOnNestedTree(t *testing.T) {
 		explanation,
 	)
 
+	var panicErr *cap.PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "sub-tree panic", panicErr.GetValue())
+
 	AssertExactMatch(t, events,
 		[]EventP{
 			WorkerStarted("root/subtree1/worker1"),
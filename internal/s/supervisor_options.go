@@ -2,6 +2,8 @@ package s
 
 import (
 	"time"
+
+	"github.com/capatazlib/go-capataz/internal/c"
 )
 
 // Opt is a type used to configure a SupervisorSpec
@@ -36,18 +38,154 @@ var WithOrder = WithStartOrder
 //
 // * OneForOne -- Only restart the failing child
 //
-// * OneForAll (Not Implemented Yet) -- Restart the failing child and all its
-// siblings[*]
+// * OneForAll -- Restart the failing child and all its siblings[*]
+//
+// * RestForOne -- Restart the failing child and all the siblings that were
+// started after it (as specified by WithStartOrder)[**]
 //
 // [*] This option may come handy when all the other siblings depend on one another
 // to work correctly.
 //
+// [**] This option may come handy when siblings started later depend on the
+// siblings that were started before them (e.g. stages of a pipeline).
+//
 func WithStrategy(s Strategy) Opt {
 	return func(spec *SupervisorSpec) {
 		spec.strategy = s
 	}
 }
 
+// WithWaitForReadiness is an Opt that specifies the supervisor must wait for
+// each child node to be ready before starting the next one (as specified by
+// WithStartOrder). Workers created with the WithReadiness option are ready once
+// they call NotifyReady, other nodes are ready once they start.
+//
+// The supervisor waits at most the given timeout for each worker; if a worker
+// is not ready on time, or if it finishes before it reports it is ready, the
+// supervisor continues starting the next child nodes. The ProcessReady event of
+// a worker that was late is reported once the worker is ready, and a worker
+// that finished is restarted as usual. A timeout lower or equal than zero
+// disables the wait. The wait only happens when the supervisor starts; restarts
+// (with any restart strategy) never wait for the readiness of children.
+//
+func WithWaitForReadiness(timeout time.Duration) Opt {
+	return func(spec *SupervisorSpec) {
+		spec.readinessTimeout = timeout
+	}
+}
+
+// WithSupervisorShutdown is an Opt that specifies how much time the supervisor
+// waits for all its children nodes to stop executing when it gets terminated.
+// When this supervisor runs as a sub-tree, its parent supervisor waits for it
+// using the same Shutdown value.
+//
+// Possible values may be:
+//
+// * Indefinitely -- The supervisor waits for all its children to stop; this is
+// the default
+//
+// * Timeout -- The supervisor stops waiting for its children once the timeout
+// is reached; children that did not stop on time are reported in the returned
+// SupervisorTerminationError, and they may keep running in the background
+//
+// Note the resource cleanup function of the supervisor (see NewSupervisorSpec)
+// is not bound by this timeout.
+//
+func WithSupervisorShutdown(shutdown c.Shutdown) Opt {
+	return func(spec *SupervisorSpec) {
+		spec.shutdown = shutdown
+	}
+}
+
+// WithShutdownTimeout is an Opt that specifies the maximum duration the
+// supervisor waits for all its children nodes to stop executing when it gets
+// terminated. It is equivalent to WithSupervisorShutdown(Timeout(d)).
+//
+func WithShutdownTimeout(d time.Duration) Opt {
+	if d <= 0 {
+		panic("WithShutdownTimeout requires a positive duration")
+	}
+	return WithSupervisorShutdown(c.Timeout(d))
+}
+
+// WithSupervisorPanicPolicy is an Opt that specifies what happens when a child
+// node of the supervisor panics. Children that specify a panic policy of their
+// own (see WithPanicPolicy), or that do not capture panics
+// (WithCapturePanic(false)), are not affected by this option; children created
+// with WithCapturePanic(true) use the panic policy of the supervisor. Sub-trees
+// that do not specify a panic policy inherit the one of their parent
+// supervisor.
+//
+// Possible values may be:
+//
+// * RestartOnPanic -- The panic is reported as an error, and the child is
+// restarted as with any other error; this is the default
+//
+// * EscalateOnPanic -- The panic is reported as an error, and the supervisor
+// fails right away without restarting the child, regardless of restart
+// tolerances
+//
+// * CrashOnPanic -- The panic is not captured, crashing the program
+//
+func WithSupervisorPanicPolicy(pp c.PanicPolicy) Opt {
+	return func(spec *SupervisorSpec) {
+		spec.panicPolicy = pp
+	}
+}
+
+// WithSupervisorLabels is an Opt that specifies labels that are attached to
+// the events the supervision system emits for the supervisor (see
+// Event.GetLabels). Children nodes and sub-trees inherit these labels; the
+// labels specified on a child node (see WithLabels) win over the inherited
+// ones.
+func WithSupervisorLabels(labels map[string]string) Opt {
+	return func(spec *SupervisorSpec) {
+		spec.labels = c.MergeLabels(spec.labels, labels)
+	}
+}
+
+// WithNodeContext is an Opt that specifies a function that gets called every
+// time a node of the supervision tree starts, including the supervisor itself.
+// The function receives the context the node is going to run with, and the
+// returned context is used instead; this is useful to attach values to the
+// context of every worker (e.g. tracing spans).
+//
+// Sub-trees use the function of their parent supervisor.
+func WithNodeContext(fn c.NodeContextFn) Opt {
+	return func(spec *SupervisorSpec) {
+		spec.nodeContextFn = fn
+	}
+}
+
+// withDynChildren is an Opt that specifies that the children of a supervisor
+// get spawned at runtime
+func withDynChildren() Opt {
+	return func(spec *SupervisorSpec) {
+		spec.dynChildren = newDynChildrenState()
+	}
+}
+
+// WithMaxChildren is an Opt that specifies the maximum number of children a
+// DynSupervisor (or the sub-tree of a NewDynSubtree) may supervise at the same
+// time. Children waiting for a delayed restart count against this limit.
+//
+// The given MaxChildrenPolicy specifies what happens when a spawn call is done
+// and the limit is reached:
+//
+// * RejectSpawn -- The spawn call returns a MaxChildrenReachedError
+//
+// * WaitForSlot -- The spawn call blocks until a child finishes, or until the
+// context of the spawn call is done
+//
+func WithMaxChildren(n int, policy MaxChildrenPolicy) Opt {
+	if n <= 0 {
+		panic("WithMaxChildren requires a positive number of children")
+	}
+	return func(spec *SupervisorSpec) {
+		spec.childrenLimit = newChildrenLimit(n, policy)
+	}
+}
+
 // WithNotifier is an Opt that specifies a callback that gets called whenever
 // the supervision system reports an Event
 //
@@ -127,8 +127,9 @@ func AssertPartialMatch(t *testing.T, evs []cap.Event, preds []EventP) {
 // ObserveDynSupervisor is an utility function that receives all the arguments
 // required to build a DynSupervisor, and a callback that when executed will
 // block until some point in the future (after we performed the side-effects we
-// are testing). This function returns the list of events that happened in the monitored
-// supervised tree, as well as any crash errors.
+// are testing). This function returns the list of lifecycle events that happened
+// in the monitored supervised tree (see LifecycleEventCollector), as well as any
+// crash errors.
 func ObserveDynSupervisor(
 	ctx0 context.Context,
 	rootName string,
@@ -147,7 +148,7 @@ func ObserveDynSupervisor(
 	// very beginning of the system setup, the order here is important as it
 	// propagates to sub-trees specified in this options
 	opts := append([]cap.Opt{
-		cap.WithNotifier(evManager.EventCollector(ctx)),
+		cap.WithNotifier(evManager.LifecycleEventCollector(ctx)),
 	}, opts0...)
 
 	// We always want to start the supervisor for test purposes, so this is
@@ -207,8 +208,9 @@ func ObserveDynSupervisor(
 // ObserveSupervisor is an utility function that receives all the arguments
 // required to build a SupervisorSpec, and a callback that when executed will
 // block until some point in the future (after we performed the side-effects we
-// are testing). This function returns the list of events that happened in the
-// monitored supervised tree, as well as any crash errors.
+// are testing). This function returns the list of lifecycle events that happened
+// in the monitored supervised tree (see LifecycleEventCollector), as well as any
+// crash errors.
 func ObserveSupervisor(
 	ctx context.Context,
 	rootName string,
@@ -247,8 +249,9 @@ func mergeNotifiers(notifiers []cap.EventNotifier) cap.EventNotifier {
 // ObserveSupervisorWithNotifiers is an utility function that receives all the arguments
 // required to build a SupervisorSpec, and a callback that when executed will
 // block until some point in the future (after we performed the side-effects we
-// are testing). This function returns the list of events that happened in the
-// monitored supervised tree, as well as any crash errors.
+// are testing). This function returns the list of lifecycle events that happened
+// in the monitored supervised tree (see LifecycleEventCollector), as well as any
+// crash errors; the given notifiers receive every event.
 func ObserveSupervisorWithNotifiers(
 	ctx0 context.Context,
 	rootName string,
@@ -267,7 +270,7 @@ func ObserveSupervisorWithNotifiers(
 	// Merge the supplied notifiers with the event collector
 	mergedNotifiers := cap.WithNotifier(
 		mergeNotifiers(
-			append([]cap.EventNotifier{evManager.EventCollector(ctx)}, notifiers...),
+			append([]cap.EventNotifier{evManager.LifecycleEventCollector(ctx)}, notifiers...),
 		),
 	)
 
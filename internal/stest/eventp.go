@@ -96,6 +96,37 @@ func (p AndP) String() string {
 	return strings.Join(acc, " && ")
 }
 
+// SourceNameP is a predicate that asserts the name of the child that caused a
+// restart matches the expected name
+type SourceNameP struct {
+	name string
+}
+
+// Call will execute predicate that checks the name of the child that caused a
+// restart
+func (p SourceNameP) Call(ev cap.Event) bool {
+	return ev.GetSourceRuntimeName() == p.name
+}
+
+func (p SourceNameP) String() string {
+	return fmt.Sprintf("source == %s", p.name)
+}
+
+// RestartCountP is a predicate that asserts the restart count reported by an
+// event matches the expected count
+type RestartCountP struct {
+	count uint32
+}
+
+// Call will execute predicate that checks the restart count of the event
+func (p RestartCountP) Call(ev cap.Event) bool {
+	return ev.GetRestartCount() == p.count
+}
+
+func (p RestartCountP) String() string {
+	return fmt.Sprintf("restartCount == %d", p.count)
+}
+
 // ErrorMsgP is a predicate that asserts the message of an error is the one
 // specified
 type ErrorMsgP struct {
@@ -121,6 +152,86 @@ func ProcessName(name string) EventP {
 	return ProcessNameP{name: name}
 }
 
+// isRestartEvent returns true if the given event reports the restart
+// bookkeeping of a process rather than a change on its lifecycle
+func isRestartEvent(ev cap.Event) bool {
+	switch ev.GetTag() {
+	case cap.SupervisorRestarting,
+		cap.RestartToleranceIncremented,
+		cap.RestartToleranceWindowReset,
+		cap.ChildRestartScheduled:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupervisorRestarting is a predicate to assert an event represents a
+// supervisor that is going to restart children because of the given source
+// child
+func SupervisorRestarting(name, sourceName string) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.SupervisorRestarting},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Supervisor},
+			SourceNameP{name: sourceName},
+		},
+	}
+}
+
+// SupervisorRestartToleranceIncremented is a predicate to assert an event
+// represents a supervisor that incremented its restart count to the given
+// value
+func SupervisorRestartToleranceIncremented(name string, count uint32) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.RestartToleranceIncremented},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Supervisor},
+			RestartCountP{count: count},
+		},
+	}
+}
+
+// SupervisorRestartToleranceWindowReset is a predicate to assert an event
+// represents a supervisor that started a new restart tolerance window
+func SupervisorRestartToleranceWindowReset(name string) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.RestartToleranceWindowReset},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Supervisor},
+		},
+	}
+}
+
+// WorkerRestartToleranceIncremented is a predicate to assert an event
+// represents a worker that incremented the restart count of its own restart
+// tolerance to the given value
+func WorkerRestartToleranceIncremented(name string, count uint32) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.RestartToleranceIncremented},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Worker},
+			RestartCountP{count: count},
+		},
+	}
+}
+
+// WorkerRestartScheduled is a predicate to assert an event represents a worker
+// which restart is going to happen after a delay
+func WorkerRestartScheduled(name string) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.ChildRestartScheduled},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Worker},
+		},
+	}
+}
+
 // SupervisorStarted is a predicate to assert an event represents a process that
 // got started
 func SupervisorStarted(name string) EventP {
@@ -241,3 +352,75 @@ func WorkerStartFailed(name string) EventP {
 		},
 	}
 }
+
+// WorkerRestartToleranceReached is a predicate to assert an event represents a
+// worker that surpassed its own restart tolerance
+func WorkerRestartToleranceReached(name string) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.ChildRestartToleranceReached},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Worker},
+		},
+	}
+}
+
+// WorkerDrained is a predicate to assert an event represents a worker that
+// finished on its own while its supervisor was draining
+func WorkerDrained(name string) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.ProcessDrained},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Worker},
+		},
+	}
+}
+
+// WorkerForceTerminated is a predicate to assert an event represents a worker
+// that got stopped by its parent supervisor at the end of a drain
+func WorkerForceTerminated(name string) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.ProcessForceTerminated},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Worker},
+		},
+	}
+}
+
+// WorkerReady is a predicate to assert an event represents a worker that
+// reported it is ready
+func WorkerReady(name string) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.ProcessReady},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Worker},
+		},
+	}
+}
+
+// WorkerAbandoned is a predicate to assert an event represents a worker that
+// did not stop within its shutdown timeout
+func WorkerAbandoned(name string) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.ProcessAbandoned},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Worker},
+		},
+	}
+}
+
+// WorkerAbandonedExited is a predicate to assert an event represents an
+// abandoned worker that finished its execution
+func WorkerAbandonedExited(name string) EventP {
+	return AndP{
+		preds: []EventP{
+			EventTagP{tag: cap.ProcessAbandonedExited},
+			ProcessNameP{name: name},
+			ProcessNodeTagP{nodeTag: c.Worker},
+		},
+	}
+}
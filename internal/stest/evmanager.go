@@ -93,9 +93,29 @@ func (em EventManager) StartCollector(ctx context.Context) {
 	}()
 }
 
-// EventCollector is used as an event notifier of a supervision system
+// EventCollector is used as an event notifier of a supervision system. The
+// collector keeps track of every event of the supervision system.
 func (em EventManager) EventCollector(ctx context.Context) func(ev cap.Event) {
+	return em.collectEvents(ctx, func(cap.Event) bool { return true })
+}
+
+// LifecycleEventCollector is like EventCollector, but it only keeps track of
+// the lifecycle events of processes; events that report the restart
+// bookkeeping of supervisors (e.g. SupervisorRestarting) are ignored.
+func (em EventManager) LifecycleEventCollector(ctx context.Context) func(ev cap.Event) {
+	return em.collectEvents(ctx, func(ev cap.Event) bool { return !isRestartEvent(ev) })
+}
+
+// collectEvents returns an event notifier that keeps track of the events that
+// match the given predicate
+func (em EventManager) collectEvents(
+	ctx context.Context,
+	shouldCollect func(cap.Event) bool,
+) func(ev cap.Event) {
 	return func(ev cap.Event) {
+		if !shouldCollect(ev) {
+			return
+		}
 		// NOTE: DO NOT REMOVE LINE BELLOW (DEBUG tool)
 		// fmt.Printf("%+v\n", ev)
 		evMsg := newEventMsg(ev)
@@ -3,11 +3,12 @@
   buildGoApplication ? pkgs.buildGoApplication,
   lib                ? pkgs.lib }:
 
-assert lib.versionAtLeast go.version "1.16";
+assert lib.versionAtLeast go.version "1.18";
 
 buildGoApplication {
   name = "go-capataz";
   version = "latest";
-  src = lib.cleanSource ./.;
+  src = lib.cleanSource ../.;
   modules = ./gomod2nix.toml;
+  inherit go;
 }